@@ -0,0 +1,15 @@
+package turbopool
+
+import "github.com/gaohao-creator/turbopool/breaker"
+
+// 熔断器配置，通过 WithBreaker 设置
+type BreakerConfig = breaker.Config
+
+// 熔断器状态，由 Stats().BreakerState 与 Hooks.OnBreakerChange 给出
+type BreakerState = breaker.State
+
+const (
+	BreakerClosed   = breaker.StateClosed   // 关闭：正常放行
+	BreakerOpen     = breaker.StateOpen     // 打开：直接拒绝提交
+	BreakerHalfOpen = breaker.StateHalfOpen // 半开：放行少量探测任务
+)
@@ -0,0 +1,196 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+type State int32
+
+const (
+	StateClosed   State = iota // 关闭：正常放行
+	StateOpen                  // 打开：直接拒绝提交
+	StateHalfOpen              // 半开：放行少量探测任务
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// 熔断器配置，零值字段使用默认值
+type Config struct {
+	FailureRate    float64       // 打开熔断的失败率阈值，默认0.5
+	MinRequests    int           // 窗口内至少完成这么多任务才判断失败率，默认20
+	Window         time.Duration // 统计窗口，窗口结束后计数清零，默认10s
+	Cooldown       time.Duration // 打开后经过多久进入半开，默认5s
+	HalfOpenProbes int           // 半开时放行的探测任务数，全部成功后关闭，默认1
+}
+
+// 按任务失败率（错误或panic）熔断的熔断器
+type Breaker struct {
+	lock     sync.Mutex
+	config   Config
+	onChange func(from, to State)
+
+	state       State
+	changedAt   time.Time // 进入当前状态的时间
+	windowStart time.Time // 当前统计窗口的开始时间
+	total       int       // 窗口内完成的任务数
+	failures    int       // 窗口内失败的任务数
+	probes      int       // 半开时已放行的探测任务数
+	successes   int       // 半开时成功的探测任务数
+	opens       int64     // 累计打开次数
+	generation  uint64    // 状态代数，状态变化或半开重新探测时递增
+	changed     bool      // 本次持锁期间状态是否变化，解锁后通知
+	changedFrom State     // 本次持锁期间变化前的状态
+}
+
+// 判断是否放行新的提交，打开状态冷却结束后进入半开
+func (b *Breaker) Allow(now time.Time) bool {
+	b.lock.Lock()
+	defer b.unlock()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.changedAt) < b.config.Cooldown {
+			return false
+		}
+		b.setState(StateHalfOpen, now)
+	case StateHalfOpen:
+		// 探测任务可能未能执行（例如提交失败），冷却时长后允许新一批探测
+		if now.Sub(b.changedAt) >= b.config.Cooldown {
+			b.changedAt = now
+			b.probes, b.successes = 0, 0
+			b.generation++ // 上一批探测的结果不再计入
+		}
+	default:
+		return true
+	}
+	if b.probes >= b.config.HalfOpenProbes {
+		return false
+	}
+	b.probes++
+	return true
+}
+
+// 获取当前状态代数，任务开始执行时记录，结束时随结果一起上报
+func (b *Breaker) Generation() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.generation
+}
+
+// 记录一次任务失败，须在该任务的 Done 之前调用；
+// gen为任务开始执行时的 Generation，状态变化前开始的任务（例如半开前就在执行的任务）的结果被忽略
+func (b *Breaker) Failure(now time.Time, gen uint64) {
+	b.lock.Lock()
+	defer b.unlock()
+	if gen != b.generation {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		b.roll(now)
+		b.failures++
+	case StateHalfOpen:
+		b.setState(StateOpen, now) // 探测失败，重新打开
+	}
+}
+
+// 记录一次任务完成（无论成败），gen与 Failure 相同
+func (b *Breaker) Done(now time.Time, gen uint64) {
+	b.lock.Lock()
+	defer b.unlock()
+	if gen != b.generation {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		b.roll(now)
+		b.total++
+		if b.total >= b.config.MinRequests && float64(b.failures)/float64(b.total) >= b.config.FailureRate {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// 获取当前状态
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+// 获取累计打开次数
+func (b *Breaker) Opens() int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.opens
+}
+
+// 统计窗口结束时清零计数
+func (b *Breaker) roll(now time.Time) {
+	if now.Sub(b.windowStart) >= b.config.Window {
+		b.windowStart = now
+		b.total, b.failures = 0, 0
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	from := b.state
+	b.state = state
+	b.changedAt = now
+	b.windowStart = now
+	b.total, b.failures = 0, 0
+	b.probes, b.successes = 0, 0
+	b.generation++
+	if state == StateOpen {
+		b.opens++
+	}
+	if !b.changed {
+		b.changed, b.changedFrom = true, from
+	}
+}
+
+// 解锁后通知本次的状态变化，回调中可以再调用熔断器的方法
+func (b *Breaker) unlock() {
+	changed, from, to := b.changed, b.changedFrom, b.state
+	b.changed = false
+	b.lock.Unlock()
+	if changed && from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
+
+// 创建熔断器，onChange在状态变化后、释放熔断器的锁之后调用
+func New(config Config, onChange func(from, to State)) *Breaker {
+	if config.FailureRate <= 0 {
+		config.FailureRate = 0.5
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 20
+	}
+	if config.Window <= 0 {
+		config.Window = 10 * time.Second
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 5 * time.Second
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	return &Breaker{config: config, onChange: onChange}
+}
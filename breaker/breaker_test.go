@@ -0,0 +1,74 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// 半开前就在执行的任务成功结束，不能被当作探测结果关闭熔断器
+func TestBreakerIgnoresTasksStartedBeforeHalfOpen(t *testing.T) {
+	b := New(Config{MinRequests: 1, Cooldown: time.Second}, nil)
+	now := time.Now()
+
+	slow := b.Generation() // 熔断前开始执行的慢任务
+	failed := b.Generation()
+	b.Failure(now, failed)
+	b.Done(now, failed)
+	if state := b.State(); state != StateOpen {
+		t.Fatalf("state = %v, want open", state)
+	}
+
+	now = now.Add(time.Second)
+	if !b.Allow(now) {
+		t.Fatal("probe not allowed after cooldown")
+	}
+	probe := b.Generation()
+
+	b.Done(now, slow)
+	if state := b.State(); state != StateHalfOpen {
+		t.Fatalf("state after stale success = %v, want half-open", state)
+	}
+	b.Done(now, probe)
+	if state := b.State(); state != StateClosed {
+		t.Fatalf("state after probe success = %v, want closed", state)
+	}
+}
+
+// 探测失败重新打开熔断器，之前开始的任务的失败不影响半开状态
+func TestBreakerProbeFailure(t *testing.T) {
+	b := New(Config{MinRequests: 1, Cooldown: time.Second}, nil)
+	now := time.Now()
+	stale := b.Generation()
+	b.Failure(now, stale)
+	b.Done(now, stale)
+
+	now = now.Add(time.Second)
+	b.Allow(now)
+	probe := b.Generation()
+	b.Failure(now, stale)
+	if state := b.State(); state != StateHalfOpen {
+		t.Fatalf("state after stale failure = %v, want half-open", state)
+	}
+	b.Failure(now, probe)
+	b.Done(now, probe)
+	if state, opens := b.State(), b.Opens(); state != StateOpen || opens != 2 {
+		t.Fatalf("state = %v, opens = %d, want open, 2", state, opens)
+	}
+}
+
+// 状态变化回调在解锁后调用，回调中可以读取熔断器状态
+func TestBreakerOnChangeOutsideLock(t *testing.T) {
+	var b *Breaker
+	var seen []State
+	b = New(Config{MinRequests: 1, Cooldown: time.Second}, func(from, to State) {
+		seen = append(seen, b.State())
+	})
+	now := time.Now()
+	gen := b.Generation()
+	b.Failure(now, gen)
+	b.Done(now, gen)
+	b.Allow(now.Add(time.Second))
+	if len(seen) != 2 || seen[0] != StateOpen || seen[1] != StateHalfOpen {
+		t.Fatalf("states seen by onChange = %v, want [open half-open]", seen)
+	}
+}
@@ -0,0 +1,52 @@
+package clock
+
+import "time"
+
+// Clock 时钟抽象，调度器和worker通过它获取时间，便于测试中控制时间流逝
+type Clock interface {
+	Now() time.Time                            // 当前时间
+	NewTicker(d time.Duration) Ticker          // 创建周期触发器
+	AfterFunc(d time.Duration, f func()) Timer // 延迟d后执行f
+}
+
+// Ticker 周期触发器
+type Ticker interface {
+	C() <-chan time.Time // 触发通道
+	Stop()               // 停止触发
+}
+
+// Timer 一次性定时器
+type Timer interface {
+	Stop() bool // 停止定时器，已触发或已停止时返回false
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{ticker: time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t *realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+// 创建基于系统时间的时钟
+func NewRealClock() Clock {
+	return realClock{}
+}
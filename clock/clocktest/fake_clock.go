@@ -0,0 +1,144 @@
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/clock"
+)
+
+// FakeClock 手动推进的时钟，只有调用 Advance 时时间才会流逝
+type FakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	timers  []*fakeTimer
+}
+
+// 当前时间
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// 创建周期触发器，触发时机由 Advance 决定
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &fakeTicker{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		period: d,
+		next:   c.now.Add(d),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// 延迟d后执行f，执行时机由 Advance 决定
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &fakeTimer{
+		clock: c,
+		when:  c.now.Add(d),
+		f:     f,
+	}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 推进时间，触发所有到期的 ticker 和 timer
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	// ticker 与标准库一致：通道满时丢弃本次触发
+	for _, t := range c.tickers {
+		for !t.next.After(now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+		}
+	}
+
+	// 取出到期的 timer，按到期时间顺序在锁外执行
+	var fired []*fakeTimer
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.when.After(now) {
+			pending = append(pending, t)
+		} else {
+			fired = append(fired, t)
+		}
+	}
+	c.timers = pending
+	c.lock.Unlock()
+
+	sort.SliceStable(fired, func(i, j int) bool {
+		return fired[i].when.Before(fired[j].when)
+	})
+	for _, t := range fired {
+		t.f()
+	}
+}
+
+// 当前仍在运行的 ticker 数量
+func (c *FakeClock) Tickers() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.tickers)
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	for i, ticker := range t.clock.tickers {
+		if ticker == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	when  time.Time
+	f     func()
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// 创建从 now 开始的假时钟
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
@@ -0,0 +1,78 @@
+package turbopool
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// 调试快照的别名，定义在 stats 子包，便于 exporter 使用
+type Debug = stats.Debug
+
+// 可展示的配置选项，函数、日志等不可序列化的选项只显示是否设置
+func (opts *Options) describe() map[string]string {
+	set := func(ok bool) string {
+		if ok {
+			return "set"
+		}
+		return "unset"
+	}
+	return map[string]string{
+		"Name":              opts.Name,
+		"Nonblocking":       strconv.FormatBool(opts.Nonblocking),
+		"MaxBlockingTasks":  strconv.Itoa(opts.MaxBlockingTasks),
+		"ExpiryDuration":    opts.ExpiryDuration.String(),
+		"MinIdleWorkers":    strconv.Itoa(opts.MinIdleWorkers),
+		"PreAlloc":          strconv.Itoa(opts.PreAlloc),
+		"WorkerMaxTasks":    strconv.Itoa(opts.WorkerMaxTasks),
+		"WorkerMaxLifetime": opts.WorkerMaxLifetime.String(),
+		"LockOSThread":      strconv.FormatBool(opts.LockOSThread),
+		"Histograms":        strconv.FormatBool(opts.Histograms),
+		"PprofLabels":       strconv.FormatBool(opts.PprofLabels),
+		"RuntimeTrace":      strconv.FormatBool(opts.RuntimeTrace),
+		"TrackTasks":        strconv.FormatBool(opts.TrackTasks),
+		"WatchdogThreshold": opts.WatchdogThreshold.String(),
+		"WatchdogInterval":  opts.WatchdogInterval.String(),
+		"PanicHandler":      set(opts.PanicHandler != nil),
+		"PanicHandlerV2":    set(opts.PanicHandlerV2 != nil),
+		"RePanic":           strconv.FormatBool(opts.RePanic),
+		"Breaker":           set(opts.Breaker != nil),
+		"Logger":            set(opts.Logger != nil),
+		"Slog":              set(opts.Slog != nil),
+	}
+}
+
+// 调度器中采集调试快照所需的方法，两种调度器都满足
+type debugScheduler interface {
+	Now() time.Time
+	Closed() bool
+	Paused() bool
+	Stats() stats.Stats
+	RunningTasks() []stats.RunningTask
+	ReadyWorkers() []stats.ReadyWorker
+	Waiters() []stats.Waiter
+}
+
+// 采集调试快照
+func newDebug(opts *Options, s debugScheduler) Debug {
+	state := "opened"
+	if s.Closed() {
+		state = "closed"
+	} else if s.Paused() {
+		state = "paused"
+	}
+	running := s.RunningTasks()
+	for i := range running {
+		running[i].Describe()
+	}
+	return Debug{
+		Now:          s.Now(),
+		State:        state,
+		Options:      opts.describe(),
+		Stats:        s.Stats(),
+		ReadyWorkers: s.ReadyWorkers(),
+		RunningTasks: running,
+		Waiters:      s.Waiters(),
+	}
+}
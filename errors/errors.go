@@ -20,4 +20,13 @@ var (
 	ErrorPoolReleaseTimeout = errors.New("release pool timeout")
 	ErrorSubmitTaskFail     = errors.New("submit task fail")
 	ErrorSubmitTaskTimeout  = errors.New("submit task timeout")
+	ErrorTaskPanic          = errors.New("task panic")
+	ErrorBreakerOpen        = errors.New("circuit breaker is open")
+
+	// Worker Errors
+	ErrorWorkerInit              = errors.New("worker init failed")
+	ErrorLockOSThreadUnsupported = errors.New("worker does not support LockOSThread")
+
+	// Exporter Errors
+	ErrorExpvarNameExists = errors.New("expvar name already exists")
 )
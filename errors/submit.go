@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 提交失败的原因
+type SubmitReason string
+
+const (
+	SubmitReasonPoolClosed      SubmitReason = "pool closed"      // 池子已关闭
+	SubmitReasonSchedulerClosed SubmitReason = "scheduler closed" // 等待期间调度器关闭
+	SubmitReasonFull            SubmitReason = "full"             // 非阻塞模式下无空闲worker或阻塞数已达上限
+	SubmitReasonTimeout         SubmitReason = "timeout"          // 等待worker期间ctx结束
+	SubmitReasonBreakerOpen     SubmitReason = "breaker open"     // 熔断器打开，快速拒绝
+	SubmitReasonUnknown         SubmitReason = "unknown"
+)
+
+// 提交任务失败的错误，包装了底层的哨兵错误，可用 errors.Is 判断具体原因，
+// 除超时外也满足 errors.Is(err, ErrorSubmitTaskFail)
+type SubmitError struct {
+	Reason   SubmitReason // 失败原因
+	PoolName string       // 池子名称
+	Waiting  int32        // 失败时阻塞等待的提交数
+	Cap      int32        // 失败时池子的容量
+	Err      error        // 底层的哨兵错误
+}
+
+func (e *SubmitError) Error() string {
+	msg := fmt.Sprintf("submit task fail: %v (waiting %d, cap %d)", e.Err, e.Waiting, e.Cap)
+	if e.PoolName != "" {
+		msg = fmt.Sprintf("pool %s: %s", e.PoolName, msg)
+	}
+	return msg
+}
+
+func (e *SubmitError) Unwrap() []error {
+	if e.Reason == SubmitReasonTimeout {
+		return []error{e.Err}
+	}
+	return []error{ErrorSubmitTaskFail, e.Err}
+}
+
+// 根据底层错误创建提交错误
+func NewSubmitError(poolName string, err error, waiting, cap int32) *SubmitError {
+	return &SubmitError{
+		Reason:   submitReason(err),
+		PoolName: poolName,
+		Waiting:  waiting,
+		Cap:      cap,
+		Err:      err,
+	}
+}
+
+func submitReason(err error) SubmitReason {
+	switch {
+	case errors.Is(err, ErrorPoolClosed):
+		return SubmitReasonPoolClosed
+	case errors.Is(err, ErrorSchedulerClosed):
+		return SubmitReasonSchedulerClosed
+	case errors.Is(err, ErrorSchedulerIsFull):
+		return SubmitReasonFull
+	case errors.Is(err, ErrorSubmitTaskTimeout):
+		return SubmitReasonTimeout
+	case errors.Is(err, ErrorBreakerOpen):
+		return SubmitReasonBreakerOpen
+	}
+	return SubmitReasonUnknown
+}
+
+// 同标准库 errors.Is，便于只导入本包时判断错误
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// 同标准库 errors.As
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// 可展示调试信息的池子，Pool[T]、PoolWithFunc 和 PoolWithState 都满足该接口
+type DebugSource interface {
+	Debug() stats.Debug
+}
+
+// 池子调试页面，类似 net/http/pprof，默认输出HTML，?format=json 或 Accept: application/json 时输出JSON，
+// ?pool=name 只展示指定的池子
+type DebugHandler struct {
+	lock  sync.RWMutex
+	pools map[string]DebugSource
+}
+
+// 注册池子，同名池子会被替换
+func (h *DebugHandler) Register(name string, pool DebugSource) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.pools[name] = pool
+}
+
+// 注销池子
+func (h *DebugHandler) Unregister(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.pools, name)
+}
+
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pools := h.snapshot(r.URL.Query().Get("pool"))
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(pools)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = debugTemplate.Execute(w, pools)
+}
+
+type poolDebug struct {
+	Name string
+	stats.Debug
+}
+
+// 按名称顺序采集池子的调试快照，name非空时只采集该池子
+func (h *DebugHandler) snapshot(name string) []poolDebug {
+	h.lock.RLock()
+	pools := make([]poolDebug, 0, len(h.pools))
+	for n, pool := range h.pools {
+		if name == "" || name == n {
+			pools = append(pools, poolDebug{Name: n, Debug: pool.Debug()})
+		}
+	}
+	h.lock.RUnlock()
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].Name < pools[j].Name
+	})
+	return pools
+}
+
+var debugTemplate = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"since": func(now, t time.Time) time.Duration {
+		return now.Sub(t).Round(time.Millisecond)
+	},
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Millisecond)
+	},
+	"keys": func(m map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>turbopool</title></head>
+<body>
+{{range .}}{{$now := .Now}}
+<h2>{{.Name}} ({{.State}})</h2>
+<p>cap {{.Stats.Cap}}, running {{.Stats.Running}}, busy {{.Stats.Busy}}, idle {{.Stats.Idle}}, waiting {{.Stats.Waiting}}, peak running {{.Stats.PeakRunning}}, peak waiting {{.Stats.PeakWaiting}}</p>
+<p>submitted {{.Stats.Submitted}}, completed {{.Stats.Completed}}, failed {{.Stats.Failed}}, panicked {{.Stats.Panicked}}, rejected {{.Stats.Rejected}}, timed out {{.Stats.TimedOut}}</p>
+<h3>Options</h3>
+<table>{{$opts := .Options}}{{range keys $opts}}
+<tr><td>{{.}}</td><td>{{index $opts .}}</td></tr>{{end}}
+</table>
+<h3>Running tasks ({{len .RunningTasks}})</h3>
+<table>
+<tr><th>id</th><th>task</th><th>labels</th><th>goroutine</th><th>started</th><th>running for</th></tr>{{range .RunningTasks}}{{$labels := .Labels}}
+<tr><td>{{.ID}}</td><td>{{.Task}}</td><td>{{range keys $labels}}{{.}}={{index $labels .}} {{end}}</td><td>{{.GoroutineID}}</td><td>{{.StartedAt.Format "15:04:05.000"}}</td><td>{{since $now .StartedAt}}</td></tr>{{end}}
+</table>
+<h3>Waiting submitters ({{len .Waiters}})</h3>
+<table>
+<tr><th>id</th><th>since</th><th>waiting for</th></tr>{{range .Waiters}}
+<tr><td>{{.ID}}</td><td>{{.Since.Format "15:04:05.000"}}</td><td>{{since $now .Since}}</td></tr>{{end}}
+</table>
+<h3>Ready workers ({{len .ReadyWorkers}})</h3>
+<table>
+<tr><th>last used</th><th>idle for</th></tr>{{range .ReadyWorkers}}
+<tr><td>{{.LastUsed.Format "15:04:05.000"}}</td><td>{{round .Idle}}</td></tr>{{end}}
+</table>
+{{else}}
+<p>no pools registered</p>
+{{end}}
+</body>
+</html>
+`))
+
+func NewDebugHandler() *DebugHandler {
+	return &DebugHandler{pools: make(map[string]DebugSource)}
+}
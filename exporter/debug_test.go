@@ -0,0 +1,78 @@
+package exporter_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gaohao-creator/turbopool"
+	"github.com/gaohao-creator/turbopool/exporter"
+)
+
+func TestDebugHandler(t *testing.T) {
+	blocked := make(chan struct{}, 1)
+	pool, _ := turbopool.NewPoolWithFuncDefaultHandler(1, turbopool.WithTrackTasks(true),
+		turbopool.WithHooks(turbopool.Hooks{OnBlock: func() { blocked <- struct{}{} }}))
+	defer pool.Release()
+
+	// 占满唯一的worker，再让一个提交方阻塞等待
+	started := make(chan struct{})
+	gate := make(chan struct{})
+	_ = pool.Submit(func() {
+		close(started)
+		<-gate
+	})
+	<-started
+	submitted := make(chan struct{})
+	go func() {
+		_ = pool.Submit(func() {})
+		close(submitted)
+	}()
+	<-blocked
+	defer func() {
+		close(gate)
+		<-submitted
+	}()
+
+	handler := exporter.NewDebugHandler()
+	handler.Register("orders", pool)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/turbopool?format=json", nil))
+	var pools []struct {
+		Name         string
+		State        string
+		Options      map[string]string
+		RunningTasks []struct{ ID, GoroutineID uint64 }
+		Waiters      []struct{ ID uint64 }
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &pools); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, rec.Body.String())
+	}
+	if len(pools) != 1 || pools[0].Name != "orders" || pools[0].State != "opened" {
+		t.Fatalf("pools = %+v", pools)
+	}
+	if p := pools[0]; len(p.RunningTasks) != 1 || p.RunningTasks[0].GoroutineID == 0 || len(p.Waiters) != 1 || p.Options["TrackTasks"] != "true" {
+		t.Fatalf("pool debug = %+v", p)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/turbopool", nil))
+	body := rec.Body.String()
+	for _, want := range []string{"<h2>orders (opened)</h2>", "Running tasks (1)", "Waiting submitters (1)"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("html missing %q:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	// 只展示指定的池子
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/turbopool?pool=missing", nil))
+	if !strings.Contains(rec.Body.String(), "no pools registered") {
+		t.Fatalf("unexpected body for missing pool:\n%s", rec.Body.String())
+	}
+}
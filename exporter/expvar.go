@@ -0,0 +1,25 @@
+package exporter
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/gaohao-creator/turbopool/errors"
+)
+
+// 保护检查与发布，避免并发发布同名变量时 expvar.Publish panic
+var expvarLock sync.Mutex
+
+// 将池子的 Stats 以 name 发布到 expvar，每次读取 /debug/vars 时实时采集；
+// name 已被占用时返回 ErrorExpvarNameExists，可并发调用
+func PublishExpvar(name string, pool StatsSource) error {
+	expvarLock.Lock()
+	defer expvarLock.Unlock()
+	if expvar.Get(name) != nil {
+		return errors.ErrorExpvarNameExists
+	}
+	expvar.Publish(name, expvar.Func(func() any {
+		return pool.Stats()
+	}))
+	return nil
+}
@@ -0,0 +1,69 @@
+package exporter_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gaohao-creator/turbopool"
+	"github.com/gaohao-creator/turbopool/exporter"
+)
+
+var expvarSeq atomic.Int32
+
+// expvar名称全局唯一且无法注销，每次调用生成新名称，重复运行（-count）时不冲突
+func expvarName(t *testing.T) string {
+	return fmt.Sprintf("%s_%d", t.Name(), expvarSeq.Add(1))
+}
+
+func TestPublishExpvar(t *testing.T) {
+	pool, _ := turbopool.NewPoolWithFuncDefaultHandler(3)
+	defer pool.Release()
+
+	name := expvarName(t)
+	if err := exporter.PublishExpvar(name, pool); err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.PublishExpvar(name, pool); err == nil {
+		t.Fatal("publishing a duplicate name should fail")
+	}
+
+	done := make(chan struct{})
+	_ = pool.Submit(func() { close(done) })
+	<-done
+
+	// 读取时才采集，能看到发布之后提交的任务
+	var stats turbopool.Stats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Cap != 3 || stats.Submitted != 1 {
+		t.Fatalf("cap = %d, submitted = %d, want 3, 1", stats.Cap, stats.Submitted)
+	}
+}
+
+// 并发发布同名变量时只有一次成功，其余返回错误而不是panic
+func TestPublishExpvarConcurrent(t *testing.T) {
+	pool, _ := turbopool.NewPoolWithFuncDefaultHandler(1)
+	defer pool.Release()
+
+	name := expvarName(t)
+	var published atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if exporter.PublishExpvar(name, pool) == nil {
+				published.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := published.Load(); n != 1 {
+		t.Fatalf("published = %d, want 1", n)
+	}
+}
@@ -0,0 +1,175 @@
+package exporter
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// 可导出指标的池子，Pool[T]、PoolWithFunc 和 PoolWithState 都满足该接口
+type StatsSource interface {
+	Stats() stats.Stats
+}
+
+// Prometheus 文本格式的指标导出器，按 pool 标签区分多个池子
+type PrometheusHandler struct {
+	lock  sync.RWMutex
+	pools map[string]StatsSource
+}
+
+// 注册池子，同名池子会被替换
+func (h *PrometheusHandler) Register(name string, pool StatsSource) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.pools[name] = pool
+}
+
+// 注销池子
+func (h *PrometheusHandler) Unregister(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.pools, name)
+}
+
+func (h *PrometheusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	buf := &bytes.Buffer{}
+	writeMetrics(buf, h.snapshot())
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+type poolStats struct {
+	name  string
+	stats stats.Stats
+}
+
+// 按名称顺序采集所有池子的快照
+func (h *PrometheusHandler) snapshot() []poolStats {
+	h.lock.RLock()
+	snapshots := make([]poolStats, 0, len(h.pools))
+	for name, pool := range h.pools {
+		snapshots = append(snapshots, poolStats{name: name, stats: pool.Stats()})
+	}
+	h.lock.RUnlock()
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].name < snapshots[j].name
+	})
+	return snapshots
+}
+
+type metric struct {
+	name  string
+	help  string
+	typ   string
+	value func(s *stats.Stats) int64
+}
+
+var metrics = []metric{
+	{"turbopool_capacity", "Maximum number of workers.", "gauge", func(s *stats.Stats) int64 { return int64(s.Cap) }},
+	{"turbopool_running_workers", "Running workers, busy and idle.", "gauge", func(s *stats.Stats) int64 { return int64(s.Running) }},
+	{"turbopool_busy_workers", "Workers executing a task.", "gauge", func(s *stats.Stats) int64 { return int64(s.Busy) }},
+	{"turbopool_idle_workers", "Workers waiting in the ready queue.", "gauge", func(s *stats.Stats) int64 { return int64(s.Idle) }},
+	{"turbopool_waiting_tasks", "Submissions blocked waiting for a worker.", "gauge", func(s *stats.Stats) int64 { return int64(s.Waiting) }},
+	{"turbopool_peak_running_workers", "Peak number of running workers.", "gauge", func(s *stats.Stats) int64 { return int64(s.PeakRunning) }},
+	{"turbopool_peak_waiting_tasks", "Peak number of waiting submissions.", "gauge", func(s *stats.Stats) int64 { return int64(s.PeakWaiting) }},
+	{"turbopool_tasks_submitted_total", "Tasks handed to a worker.", "counter", func(s *stats.Stats) int64 { return s.Submitted }},
+	{"turbopool_tasks_completed_total", "Tasks completed without panic.", "counter", func(s *stats.Stats) int64 { return s.Completed }},
+	{"turbopool_tasks_failed_total", "Tasks that could not run, e.g. the scheduler closed while waiting or worker init failed.", "counter", func(s *stats.Stats) int64 { return s.Failed }},
+	{"turbopool_tasks_panicked_total", "Tasks that panicked.", "counter", func(s *stats.Stats) int64 { return s.Panicked }},
+	{"turbopool_tasks_rejected_total", "Submissions rejected because the pool was full or closed or the circuit breaker was open.", "counter", func(s *stats.Stats) int64 { return s.Rejected }},
+	{"turbopool_tasks_dropped_total", "Accepted tasks dropped before starting when the pool was released.", "counter", func(s *stats.Stats) int64 { return s.Dropped }},
+	{"turbopool_tasks_timed_out_total", "Submissions that timed out waiting for a worker.", "counter", func(s *stats.Stats) int64 { return s.TimedOut }},
+	{"turbopool_workers_created_total", "Workers started.", "counter", func(s *stats.Stats) int64 { return s.WorkersCreated }},
+	{"turbopool_workers_expired_total", "Idle workers reaped after expiry.", "counter", func(s *stats.Stats) int64 { return s.WorkersExpired }},
+	{"turbopool_breaker_state", "Circuit breaker state: 0 closed, 1 open, 2 half-open.", "gauge", func(s *stats.Stats) int64 { return int64(s.BreakerState) }},
+	{"turbopool_breaker_opens_total", "Times the circuit breaker opened.", "counter", func(s *stats.Stats) int64 { return s.BreakerOpens }},
+}
+
+func writeMetrics(buf *bytes.Buffer, pools []poolStats) {
+	for _, m := range metrics {
+		writeHeader(buf, m.name, m.help, m.typ)
+		for i := range pools {
+			writeSample(buf, m.name, pools[i].name, "", float64(m.value(&pools[i].stats)))
+		}
+	}
+
+	writeHeader(buf, "turbopool_workers_recycled_total", "Workers exited after reaching a recycle limit.", "counter")
+	for i := range pools {
+		s := &pools[i].stats
+		writeSample(buf, "turbopool_workers_recycled_total", pools[i].name, `reason="tasks"`, float64(s.RecycledByTasks))
+		writeSample(buf, "turbopool_workers_recycled_total", pools[i].name, `reason="lifetime"`, float64(s.RecycledByLifetime))
+	}
+
+	writeHistogram(buf, "turbopool_task_wait_seconds", "Time tasks waited for a worker.", pools,
+		func(s *stats.Stats) stats.HistogramSnapshot { return s.WaitLatency })
+	writeHistogram(buf, "turbopool_task_exec_seconds", "Task execution time.", pools,
+		func(s *stats.Stats) stats.HistogramSnapshot { return s.ExecLatency })
+}
+
+// 写入直方图，未开启直方图的池子不输出
+func writeHistogram(buf *bytes.Buffer, name, help string, pools []poolStats, get func(s *stats.Stats) stats.HistogramSnapshot) {
+	header := false
+	for i := range pools {
+		h := get(&pools[i].stats)
+		if h.Buckets == nil {
+			continue
+		}
+		if !header {
+			writeHeader(buf, name, help, "histogram")
+			header = true
+		}
+		var cumulative int64
+		for j, n := range h.Buckets {
+			cumulative += n
+			le := "+Inf"
+			if j < stats.HistogramBuckets-1 {
+				le = formatFloat(stats.BucketUpperBound(j).Seconds())
+			}
+			writeSample(buf, name+"_bucket", pools[i].name, `le="`+le+`"`, float64(cumulative))
+		}
+		writeSample(buf, name+"_sum", pools[i].name, "", h.Sum.Seconds())
+		// 计数取自各桶之和，与 +Inf 桶一致；各原子计数分别读取，直方图自带的Count可能与桶对不上
+		writeSample(buf, name+"_count", pools[i].name, "", float64(cumulative))
+	}
+}
+
+func writeHeader(buf *bytes.Buffer, name, help, typ string) {
+	buf.WriteString("# HELP " + name + " " + help + "\n")
+	buf.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+func writeSample(buf *bytes.Buffer, name, pool, labels string, value float64) {
+	buf.WriteString(name)
+	buf.WriteString(`{pool="`)
+	buf.WriteString(escapeLabel(pool))
+	buf.WriteByte('"')
+	if labels != "" {
+		buf.WriteByte(',')
+		buf.WriteString(labels)
+	}
+	buf.WriteString("} ")
+	buf.WriteString(formatFloat(value))
+	buf.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// 创建 Prometheus 指标导出器
+func NewPrometheusHandler() *PrometheusHandler {
+	return &PrometheusHandler{
+		pools: make(map[string]StatsSource),
+	}
+}
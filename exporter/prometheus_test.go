@@ -0,0 +1,52 @@
+package exporter_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gaohao-creator/turbopool"
+	"github.com/gaohao-creator/turbopool/exporter"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	pool, _ := turbopool.NewPoolWithFuncDefaultHandler(4, turbopool.WithHistograms(true))
+	defer pool.Release()
+	done := make(chan struct{})
+	_ = pool.Submit(func() { close(done) })
+	<-done
+
+	generic, _ := turbopool.NewPoolDefaultWorkers(2, func(int) {})
+	defer generic.Release()
+
+	handler := exporter.NewPrometheusHandler()
+	handler.Register("func", pool)
+	handler.Register(`gen"eric`, generic)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE turbopool_capacity gauge\n",
+		`turbopool_capacity{pool="func"} 4` + "\n",
+		`turbopool_capacity{pool="gen\"eric"} 2` + "\n",
+		`turbopool_tasks_submitted_total{pool="func"} 1` + "\n",
+		`turbopool_tasks_dropped_total{pool="func"} 0` + "\n",
+		`turbopool_workers_recycled_total{pool="func",reason="tasks"} 0` + "\n",
+		"# TYPE turbopool_task_wait_seconds histogram\n",
+		`turbopool_task_wait_seconds_bucket{pool="func",le="+Inf"} 1` + "\n",
+		`turbopool_task_wait_seconds_count{pool="func"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	// 未开启直方图的池子不输出直方图
+	if strings.Contains(body, `turbopool_task_wait_seconds_count{pool="gen\"eric"}`) {
+		t.Fatal("histogram exported for pool without histograms")
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("content type = %q", ct)
+	}
+}
@@ -2,10 +2,12 @@ module github.com/gaohao-creator/turbopool
 
 go 1.24.12
 
-require github.com/xiajingge/logger v1.0.2
+require (
+	github.com/xiajingge/logger v1.0.2
+	golang.org/x/sync v0.19.0
+)
 
 require (
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.1 // indirect
-	golang.org/x/sync v0.19.0 // indirect
 )
@@ -0,0 +1,16 @@
+package turbopool
+
+import "github.com/gaohao-creator/turbopool/hooks"
+
+// 生命周期与任务事件钩子，通过 WithHooks 设置
+type Hooks = hooks.Hooks
+
+// worker退出原因，由 Hooks.OnWorkerExit 接收
+type WorkerExitReason = hooks.ExitReason
+
+const (
+	WorkerExitFinished = hooks.ExitFinished // 被通知结束（过期清理、释放）
+	WorkerExitClosed   = hooks.ExitClosed   // 调度器已关闭或就绪队列已满，无法归还
+	WorkerExitRecycled = hooks.ExitRecycled // 达到任务数或存活时长上限，主动回收
+	WorkerExitPanicked = hooks.ExitPanicked // 任务之外的panic导致退出，任务panic不会使worker退出
+)
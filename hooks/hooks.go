@@ -0,0 +1,46 @@
+package hooks
+
+import (
+	"time"
+
+	"github.com/gaohao-creator/turbopool/breaker"
+)
+
+// worker退出原因
+type ExitReason int
+
+const (
+	ExitFinished ExitReason = iota // 被通知结束（过期清理、释放）
+	ExitClosed                     // 调度器已关闭或就绪队列已满，无法归还
+	ExitRecycled                   // 达到任务数或存活时长上限，主动回收
+	ExitPanicked                   // worker启动、退出钩子等任务之外的panic导致退出
+)
+
+func (r ExitReason) String() string {
+	switch r {
+	case ExitFinished:
+		return "finished"
+	case ExitClosed:
+		return "closed"
+	case ExitRecycled:
+		return "recycled"
+	case ExitPanicked:
+		return "panicked"
+	}
+	return "unknown"
+}
+
+// Hooks 生命周期与任务事件钩子，未设置的钩子不会被调用。
+// 钩子在worker或提交方的goroutine中同步执行，应尽量轻量且不能阻塞；
+// 钩子都在不持有调度器和熔断器的锁时调用，可以读取池子状态（Stats、Debug、Waiting 等）。
+type Hooks struct {
+	OnWorkerStart func()                               // worker goroutine启动
+	OnWorkerExit  func(reason ExitReason)              // worker goroutine退出
+	OnTaskStart   func()                               // 任务开始执行
+	OnTaskEnd     func(d time.Duration, panicked bool) // 任务执行结束
+	OnReject      func(err error)                      // 提交被拒绝（调度器已满、已关闭或熔断器打开）
+	OnBlock       func()                               // 提交方开始阻塞等待worker
+	OnUnblock     func()                               // 提交方结束阻塞等待
+
+	OnBreakerChange func(from, to breaker.State) // 熔断器状态变化
+}
@@ -1,6 +1,16 @@
 package turbopool
 
-import "time"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/breaker"
+	"github.com/gaohao-creator/turbopool/clock"
+	"github.com/gaohao-creator/turbopool/tracing"
+)
+
+// 池子名称的pprof标签键
+const PprofLabelPool = "turbopool.pool"
 
 const (
 	STATE_OPENED = int32(iota)
@@ -13,20 +23,71 @@ type Logger interface {
 }
 
 type Options struct {
+	// Pool name, used in pprof labels and diagnostics.
+	Name string
 	// Blocking option, blocking submit task when no free worker.
 	Nonblocking bool
 	// blocking submit task max value.
 	MaxBlockingTasks int
 	// Worker expiry duration
 	ExpiryDuration time.Duration
+	// Minimum ready workers kept when clearing expired workers.
+	// Custom Workers must implement KeepIdleClearer, otherwise no worker is kept.
+	MinIdleWorkers int
+	// Workers started when the pool is created.
+	PreAlloc int
+	// Worker exits after running this many tasks, 0 means no limit.
+	WorkerMaxTasks int
+	// Worker exits after living this long, 0 means no limit.
+	WorkerMaxLifetime time.Duration
+	// Lock each worker goroutine to its own OS thread for its lifetime.
+	// Custom workers must implement ThreadLocker, otherwise creating the pool fails.
+	LockOSThread bool
+	// Record queue wait and execution latency histograms.
+	Histograms bool
+	// Record a runtime/trace task per submission with wait and execute regions.
+	RuntimeTrace bool
+	// Run each task under pprof labels: the pool name plus labels from the submit context.
+	PprofLabels bool
 	// Recover panic handler.
 	PanicHandler func(any)
+	// Recover panic handler with task, pool and stack, takes precedence over PanicHandler.
+	PanicHandlerV2 func(PanicInfo)
+	// Panic again after the panic is handled, crashing the process.
+	RePanic bool
 	// Custom Logger
 	Logger Logger
+	// Structured logger, takes precedence over Logger for panics.
+	Slog *slog.Logger
+	// Levels of structured log events.
+	SlogLevels SlogLevels
+	// Lifecycle and task event hooks.
+	Hooks Hooks
+	// Circuit breaker on the failure rate of tasks, nil disables it.
+	Breaker *breaker.Config
+	// Tracer creates spans for queue wait and execution, default is no-op.
+	Tracer tracing.Tracer
+	// Record running tasks for the debug handler, implied by the watchdog.
+	TrackTasks bool
+	// Tasks running longer than this are reported by the watchdog, 0 disables it.
+	WatchdogThreshold time.Duration
+	// Interval between watchdog scans, default is WatchdogThreshold.
+	WatchdogInterval time.Duration
+	// Slow task callback, default logs through Slog or Logger.
+	OnSlowTask func(SlowTask)
+	// Clock used by expiry checks, default is system clock.
+	Clock clock.Clock
 }
 
 type Option func(opts *Options)
 
+// 设置池子名称，用于pprof标签和诊断信息
+func WithName(name string) Option {
+	return func(opts *Options) {
+		opts.Name = name
+	}
+}
+
 func WithNonblocking(nonblocking bool) Option {
 	return func(opts *Options) {
 		opts.Nonblocking = nonblocking
@@ -45,23 +106,153 @@ func WithExpiryDuration(expiryDuration time.Duration) Option {
 	}
 }
 
+func WithMinIdleWorkers(minIdleWorkers int) Option {
+	return func(opts *Options) {
+		opts.MinIdleWorkers = minIdleWorkers
+	}
+}
+
+func WithPreAlloc(preAlloc int) Option {
+	return func(opts *Options) {
+		opts.PreAlloc = preAlloc
+	}
+}
+
+func WithWorkerMaxTasks(workerMaxTasks int) Option {
+	return func(opts *Options) {
+		opts.WorkerMaxTasks = workerMaxTasks
+	}
+}
+
+func WithWorkerMaxLifetime(workerMaxLifetime time.Duration) Option {
+	return func(opts *Options) {
+		opts.WorkerMaxLifetime = workerMaxLifetime
+	}
+}
+
+func WithLockOSThread(lockOSThread bool) Option {
+	return func(opts *Options) {
+		opts.LockOSThread = lockOSThread
+	}
+}
+
+func WithHistograms(histograms bool) Option {
+	return func(opts *Options) {
+		opts.Histograms = histograms
+	}
+}
+
 func WithPanicHandler(panicHandler func(any)) Option {
 	return func(opts *Options) {
 		opts.PanicHandler = panicHandler
 	}
 }
 
+// 设置带任务、池子名称和堆栈信息的panic处理器，优先于 WithPanicHandler
+func WithPanicHandlerV2(panicHandler func(PanicInfo)) Option {
+	return func(opts *Options) {
+		opts.PanicHandlerV2 = panicHandler
+	}
+}
+
+// panic处理完后重新抛出，使进程崩溃
+func WithRePanic(rePanic bool) Option {
+	return func(opts *Options) {
+		opts.RePanic = rePanic
+	}
+}
+
+func WithHooks(hooks Hooks) Option {
+	return func(opts *Options) {
+		opts.Hooks = hooks
+	}
+}
+
+func WithSlog(logger *slog.Logger) Option {
+	return func(opts *Options) {
+		opts.Slog = logger
+	}
+}
+
+// 设置结构化日志全部事件的级别，零值字段即 slog.LevelInfo；通常在 DefaultSlogLevels 的基础上修改，只调整个别事件用 WithSlogLevel
+func WithSlogLevels(levels SlogLevels) Option {
+	return func(opts *Options) {
+		opts.SlogLevels = levels
+	}
+}
+
+// 设置单个结构化日志事件的级别，其余事件保留当前级别，可以设为包括 slog.LevelInfo 在内的任意级别
+func WithSlogLevel(event SlogEvent, level slog.Level) Option {
+	return func(opts *Options) {
+		if l := opts.SlogLevels.level(event); l != nil {
+			*l = level
+		}
+	}
+}
+
+// 任务执行期间为worker goroutine设置pprof标签（池子名称及SubmitContext中的标签），CPU profile可按任务类型区分
+func WithPprofLabels(pprofLabels bool) Option {
+	return func(opts *Options) {
+		opts.PprofLabels = pprofLabels
+	}
+}
+
+// 开启 runtime/trace 集成：每次提交创建trace.Task，等待与执行分别是独立的区域，并记录worker生命周期事件
+func WithRuntimeTrace(runtimeTrace bool) Option {
+	return func(opts *Options) {
+		opts.RuntimeTrace = runtimeTrace
+	}
+}
+
+// 开启熔断器：任务失败（返回错误或panic）率过高时打开，快速拒绝提交，冷却后半开探测
+func WithBreaker(config breaker.Config) Option {
+	return func(opts *Options) {
+		opts.Breaker = &config
+	}
+}
+
+// 登记正在执行的任务，供调试页面展示
+func WithTrackTasks(trackTasks bool) Option {
+	return func(opts *Options) {
+		opts.TrackTasks = trackTasks
+	}
+}
+
+// 开启慢任务巡检：每隔interval检查一次，执行超过threshold的任务报告给onSlowTask（为nil时写日志）
+func WithWatchdog(threshold, interval time.Duration, onSlowTask func(SlowTask)) Option {
+	return func(opts *Options) {
+		opts.WatchdogThreshold = threshold
+		opts.WatchdogInterval = interval
+		opts.OnSlowTask = onSlowTask
+	}
+}
+
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(opts *Options) {
+		opts.Tracer = tracer
+	}
+}
+
 func WithLogger(logger Logger) Option {
 	return func(opts *Options) {
 		opts.Logger = logger
 	}
 }
 
+func WithClock(clock clock.Clock) Option {
+	return func(opts *Options) {
+		opts.Clock = clock
+	}
+}
+
 func NewOptions(options ...Option) *Options {
 	opts := &Options{
 		Nonblocking:      false,
 		MaxBlockingTasks: 0,
 		ExpiryDuration:   1000 * time.Millisecond,
+		Clock:            clock.NewRealClock(),
+		Tracer:           tracing.NewNoopTracer(),
+		SlogLevels:       DefaultSlogLevels(),
 	}
 	for _, option := range options {
 		option(opts)
@@ -0,0 +1,55 @@
+package turbopool
+
+import (
+	"log/slog"
+	"time"
+)
+
+// panic的详细信息，由 PanicHandlerV2 接收
+type PanicInfo struct {
+	Value     any       // recover()的返回值；任务未能执行时为错误，如包装 ErrorWorkerInit 的worker初始化错误
+	Stack     []byte    // panic时的goroutine堆栈
+	Task      any       // 发生panic的任务：Pool[T]中为T，PoolWithFunc中为提交的函数；非任务引起的panic为nil
+	PoolName  string    // 池子名称，见 WithName
+	StartedAt time.Time // 任务开始执行的时间，非任务引起的panic为零值
+}
+
+// 处理完panic后重新抛出时携带原始值，worker退出时据此直接崩溃而不再重复处理
+type rePanic struct {
+	value any
+}
+
+// 统一处理 panic，优先使用 PanicHandlerV2、PanicHandler，其次是结构化日志和普通日志；
+// 开启 RePanic 时处理完后重新抛出
+func (opts *Options) handlePanic(info PanicInfo) {
+	msg := "task panicked" // 任务panic后worker继续运行
+	if info.StartedAt.IsZero() {
+		msg = "worker exits from panic"
+	}
+	opts.reportPanic(msg, "panic", info)
+	if opts.RePanic {
+		panic(&rePanic{value: info.Value})
+	}
+}
+
+// 报告未能执行的任务，与panic使用同一套处理器，但不会按 RePanic 崩溃
+func (opts *Options) handleTaskFailure(info PanicInfo) {
+	opts.reportPanic("task not executed", "error", info)
+}
+
+func (opts *Options) reportPanic(msg, key string, info PanicInfo) {
+	switch {
+	case opts.PanicHandlerV2 != nil:
+		opts.PanicHandlerV2(info)
+	case opts.PanicHandler != nil:
+		opts.PanicHandler(info.Value)
+	case opts.Slog != nil:
+		attrs := []slog.Attr{slog.Any(key, info.Value)}
+		if info.Stack != nil {
+			attrs = append(attrs, slog.String("stack", string(info.Stack)))
+		}
+		opts.logAttrs(opts.SlogLevels.Panic, msg, attrs...)
+	case opts.Logger != nil:
+		opts.Logger.Printf("%s: %v\n%s\n", msg, info.Value, info.Stack)
+	}
+}
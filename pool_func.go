@@ -2,6 +2,9 @@ package turbopool
 
 import (
 	"context"
+	"log/slog"
+	"runtime/pprof"
+	"runtime/trace"
 
 	"sync/atomic"
 	"time"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/gaohao-creator/turbopool/errors"
 	"github.com/gaohao-creator/turbopool/scheduler_func"
+	"github.com/gaohao-creator/turbopool/tracing"
 )
 
 type PoolWithFunc struct {
@@ -23,18 +27,76 @@ type PoolWithFunc struct {
 	clockCtxCancel *ctx.CtxCancel
 	// 清理上下文，池子关闭时取消
 	clearCtxCancel *ctx.CtxCancel
+	// 慢任务巡检上下文，池子关闭时取消
+	watchdogCtxCancel *ctx.CtxCancel
+	// 任务运行上下文，ReleaseContext 的ctx结束时取消，通知仍在执行的任务尽快结束
+	runCtxCancel *ctx.CtxCancel
 }
 
 // 提交任务到worker，worker从调度器获取
 func (p *PoolWithFunc) Submit(task func()) error {
+	return p.SubmitContext(context.Background(), task)
+}
+
+// 携带上下文提交任务：失败时返回 *errors.SubmitError，阻塞等待worker时taskCtx结束则其包装 ErrorSubmitTaskTimeout，
+// taskCtx中的追踪信息会传递给等待与执行阶段的span；执行阶段span的ctx随 Context 取消，而不是随taskCtx取消
+func (p *PoolWithFunc) SubmitContext(taskCtx context.Context, task func()) error {
 	if p.Closed() {
-		return errors.ErrorPoolClosed
+		p.scheduler.Reject(errors.ErrorPoolClosed)
+		return p.submitError(errors.ErrorPoolClosed)
+	}
+	taskCtx, traceTask := p.options.startTraceTask(taskCtx)
+	waitCtx, span := p.options.Tracer.Start(taskCtx, tracing.SpanWait)
+	var region *trace.Region
+	if traceTask != nil {
+		region = trace.StartRegion(taskCtx, TraceRegionWait)
+	}
+	w, err := p.scheduler.Get(waitCtx)
+	if region != nil {
+		region.End()
+		if err != nil {
+			traceTask.End() // 未分配到worker，任务到此结束
+		}
 	}
-	if w, err := p.scheduler.Get(); err == nil {
-		w.Put(task)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+	if err == nil {
+		scheduler_func.PutWithContext(w, taskCtx, task)
 		return nil
 	}
-	return errors.ErrorSubmitTaskFail
+	return p.submitError(err)
+}
+
+// 包装提交失败的原因和池子当前状态
+func (p *PoolWithFunc) submitError(err error) error {
+	return errors.NewSubmitError(p.options.Name, err, p.Waiting(), p.Cap())
+}
+
+// 提交返回错误的任务，开启熔断器时返回的错误计入失败率
+func (p *PoolWithFunc) SubmitWithError(task func() error) error {
+	return p.Submit(func() {
+		p.runWithError(task)
+	})
+}
+
+// 执行返回错误的任务，开启熔断器时错误计入失败率
+func (p *PoolWithFunc) runWithError(task func() error) {
+	b := p.scheduler.Breaker()
+	if b == nil {
+		_ = task()
+		return
+	}
+	gen := b.Generation() // 与执行阶段一样按任务开始时的状态代数上报
+	if err := task(); err != nil {
+		b.Failure(p.scheduler.Now(), gen)
+	}
+}
+
+// 携带pprof标签提交任务，labels为成对的键值，开启 WithPprofLabels 时在任务执行期间生效
+func (p *PoolWithFunc) SubmitWithLabels(task func(), labels ...string) error {
+	return p.SubmitContext(pprof.WithLabels(context.Background(), pprof.Labels(labels...)), task)
 }
 
 // 释放调度器资源
@@ -44,6 +106,7 @@ func (p *PoolWithFunc) Release() {
 	// 停止时钟和清理goroutine
 	p.clearCtxCancel.Cancel()
 	p.clockCtxCancel.Cancel()
+	p.watchdogCtxCancel.Cancel()
 }
 
 // 等待调度器所有任务完成
@@ -59,6 +122,7 @@ func (p *PoolWithFunc) ReleaseWithWait() {
 	// 停止时钟和清理goroutine
 	p.clearCtxCancel.Cancel()
 	p.clockCtxCancel.Cancel()
+	p.watchdogCtxCancel.Cancel()
 }
 
 // 带超时的释放调度器
@@ -69,15 +133,92 @@ func (p *PoolWithFunc) ReleaseWithTimeout(t time.Duration) error {
 	p.scheduler.Release()
 	select {
 	case <-ctx.Done():
+		if p.options.Slog != nil {
+			p.options.logAttrs(p.options.SlogLevels.ReleaseTimeout, "release pool timeout",
+				slog.Duration("timeout", t), slog.Int("running", int(p.Running())), slog.Int("waiting", int(p.Waiting())))
+		}
 		return errors.ErrorPoolReleaseTimeout
 	case <-p.scheduler.Done():
 	}
 	// 停止时钟和清理goroutine
 	p.clearCtxCancel.Cancel()
 	p.clockCtxCancel.Cancel()
+	p.watchdogCtxCancel.Cancel()
 	return nil
 }
 
+// 使用ctx控制的释放：不再接受提交，等待已接受的任务执行完；drop为true时丢弃尚未开始执行的任务并在报告中返回。
+// ctx结束时取消 Context 返回的上下文，通知仍在执行的任务尽快结束，并返回 ErrorPoolReleaseTimeout
+func (p *PoolWithFunc) ReleaseContext(releaseCtx context.Context, drop bool) (ReleaseReport[func()], error) {
+	before := p.scheduler.Stats()
+	p.Close()
+	if drop {
+		p.scheduler.DropPending()
+	}
+	p.scheduler.Release()
+	var err error
+	select {
+	case <-p.scheduler.Done():
+		// 停止时钟和清理goroutine
+		p.clearCtxCancel.Cancel()
+		p.clockCtxCancel.Cancel()
+		p.watchdogCtxCancel.Cancel()
+	case <-releaseCtx.Done():
+		p.runCtxCancel.Cancel()
+		err = errors.ErrorPoolReleaseTimeout
+		if drop {
+			p.scheduler.WaitPending() // 被恢复的worker异步丢弃手中的任务，等它们登记后再取回
+		}
+	}
+	report := ReleaseReport[func()]{
+		Completed: completedSince(before, p.scheduler.Stats()),
+		Dropped:   p.scheduler.Dropped(),
+	}
+	if err != nil {
+		report.Running = p.Running() // 就绪worker已清空，剩下的都在执行任务
+		if p.options.Slog != nil {
+			p.options.logAttrs(p.options.SlogLevels.ReleaseTimeout, "release pool timeout",
+				slog.Int("running", int(report.Running)), slog.Int("dropped", len(report.Dropped)))
+		}
+	}
+	return report, err
+}
+
+// 释放池子并等待执行中的任务结束，返回已分配到worker但未开始执行的任务，便于持久化或转交其他池子。
+// 池子没有任务队列，这类任务只出现在暂停期间（每个worker最多一个）或提交与释放交错的瞬间，未暂停时通常返回空；
+// 释放时阻塞等待worker的提交返回 ErrorSchedulerClosed，任务仍在提交方手中，不会出现在结果里
+func (p *PoolWithFunc) Drain() []func() {
+	report, _ := p.ReleaseContext(context.Background(), true)
+	return report.Dropped
+}
+
+// 带超时的 Drain，超时时仍返回已收集到的任务和 ErrorPoolReleaseTimeout
+func (p *PoolWithFunc) ReleaseAndCollect(t time.Duration) ([]func(), error) {
+	releaseCtx, cancel := context.WithTimeout(context.Background(), t)
+	defer cancel()
+	report, err := p.ReleaseContext(releaseCtx, true)
+	return report.Dropped, err
+}
+
+// 任务运行上下文，ReleaseContext 的ctx结束时取消，执行阶段的span会记录取消。
+// 任务函数本身拿不到ctx，长时间运行的任务需要自行监听它才能尽快退出，不监听的任务不会被中断
+func (p *PoolWithFunc) Context() context.Context {
+	return p.runCtxCancel.Ctx
+}
+
+// 调整池子容量：扩容后立即唤醒阻塞的提交方，缩容时多出的worker在手头任务结束后退出；cap不大于0时不做任何事
+func (p *PoolWithFunc) Scale(cap int) {
+	if cap <= 0 {
+		return
+	}
+	p.scheduler.Scale(int32(cap))
+}
+
+// 预先启动n个worker，避免首批任务承担创建开销，返回实际启动的数量
+func (p *PoolWithFunc) Prewarm(n int) int {
+	return p.scheduler.Prewarm(n)
+}
+
 /* ------------------------------------------------- */
 /* 监控需求 */
 /* ------------------------------------------------- */
@@ -97,34 +238,89 @@ func (p *PoolWithFunc) Running() int32 {
 	return p.scheduler.Running()
 }
 
-// // 获取调度器中正在工作的worker数量
-// func (p *PoolWithFunc) Working() int32 {
-// 	return p.scheduler.Working()
-// }
+// 获取调度器中正在工作的worker数量
+func (p *PoolWithFunc) Working() int32 {
+	return p.scheduler.Working()
+}
 
 // 获取调度器中等待执行的任务数量
 func (p *PoolWithFunc) Waiting() int32 {
 	return p.scheduler.Waiting()
 }
 
-// 关闭池子
+// 获取因任务数达到上限而回收的worker数量
+func (p *PoolWithFunc) RecycledByTasks() int64 {
+	return p.scheduler.RecycledByTasks()
+}
+
+// 获取因存活时长达到上限而回收的worker数量
+func (p *PoolWithFunc) RecycledByLifetime() int64 {
+	return p.scheduler.RecycledByLifetime()
+}
+
+// 获取池子运行状态快照
+func (p *PoolWithFunc) Stats() Stats {
+	return p.scheduler.Stats()
+}
+
+// 获取池子的调试快照：配置、状态、就绪worker、正在执行的任务和阻塞等待的提交方
+func (p *PoolWithFunc) Debug() Debug {
+	return newDebug(p.options, p.scheduler)
+}
+
+// 关闭池子，不再接受提交；只改变池子状态，已就绪和执行中的worker不受影响，可以用 Open 重新打开
 func (p *PoolWithFunc) Close() {
 	p.state.Store(STATE_CLOSED)
 }
 
-// 打开池子
+// 打开池子，重新接受提交；已释放的池子需要使用 Reboot
 func (p *PoolWithFunc) Open() {
 	p.state.Store(STATE_OPENED)
 }
 
-// 获取调度器是否已关闭
+// 重启已释放的池子：重新打开调度器、重建完成信号，并重启过期清理和慢任务巡检；
+// 未关闭的池子不做任何事；重启前仍在 Wait 的调用随之返回。不能与 Release 系列方法并发调用
+func (p *PoolWithFunc) Reboot() {
+	if !p.Closed() {
+		return
+	}
+	// 超时释放时后台goroutine可能仍在运行，先停止
+	p.clearCtxCancel.Cancel()
+	p.watchdogCtxCancel.Cancel()
+	p.scheduler.Reboot()
+	if p.runCtxCancel.Ctx.Err() != nil {
+		p.runCtxCancel = ctx.NewContextWithCancel(context.Background())
+		p.scheduler.SetRunContext(p.runCtxCancel.Ctx)
+	}
+	p.state.Store(STATE_OPENED)
+	p.clear(p.options.ExpiryDuration)
+	p.watchdog()
+}
+
+// 暂停执行新任务，已在执行的任务不受影响；释放池子时自动恢复。
+// 暂停期间每个worker持有一个待执行的任务，worker用完后提交照常阻塞，非阻塞池子则返回 ErrorSchedulerIsFull
+func (p *PoolWithFunc) Pause() {
+	p.scheduler.Pause()
+}
+
+// 恢复执行暂停期间积压的任务
+func (p *PoolWithFunc) Resume() {
+	p.scheduler.Resume()
+}
+
+// 获取池子是否已暂停
+func (p *PoolWithFunc) Paused() bool {
+	return p.scheduler.Paused()
+}
+
+// 获取池子是否已关闭：Close 关闭池子或调度器已释放
 func (p *PoolWithFunc) Closed() bool {
-	return p.scheduler.Closed()
+	return p.state.Load() == STATE_CLOSED || p.scheduler.Closed()
 }
 
-// 获取调度器是否已打开
+// 获取池子是否已打开
 func (p *PoolWithFunc) Opened() bool {
-	return p.scheduler.Opened()
+	return !p.Closed()
 }
 
 /* ------------------------------------------------- */
@@ -161,18 +357,20 @@ func (p *PoolWithFunc) clear(d time.Duration) {
 	if d == 0 {
 		return
 	}
-	p.clearCtxCancel = ctx.NewContextWithCancel(context.Background())
+	// goroutine持有本次创建的上下文，重启时替换字段不影响旧的goroutine退出
+	clearCtx := ctx.NewContextWithCancel(context.Background())
+	p.clearCtxCancel = clearCtx
+	// ticker 在启动goroutine前创建，保证测试中推进时钟时已经注册
+	ticker := p.options.Clock.NewTicker(d)
 	go func() {
-		ticker := time.NewTicker(d)
 		defer func() {
 			ticker.Stop()
 		}()
-		context := p.clearCtxCancel
 		for {
 			select {
-			case <-context.Ctx.Done():
+			case <-clearCtx.Ctx.Done():
 				return
-			case <-ticker.C:
+			case <-ticker.C():
 			}
 			if p.Closed() {
 				break
@@ -183,21 +381,52 @@ func (p *PoolWithFunc) clear(d time.Duration) {
 	}()
 }
 
+// 启动慢任务巡检，池子释放时停止
+func (p *PoolWithFunc) watchdog() {
+	p.watchdogCtxCancel = ctx.NewContextWithCancel(context.Background())
+	startWatchdog(p.watchdogCtxCancel.Ctx, p.options, p.scheduler.RunningTasks)
+}
+
 type WorkersWithFuncCreator func(int) (scheduler_func.WorkersWithFunc, error)
 
 func WorkerWithFuncCreator(s scheduler_func.Scheduler) scheduler_func.WorkerWithFunc {
 	return scheduler_func.NewWorkerWithFunc(s)
 }
 
+// worker工厂函数，调度器需要新的worker时调用
+type WorkerWithFuncFactory func(scheduler_func.Scheduler) scheduler_func.WorkerWithFunc
+
 func NewPoolWithFunc(
 	cap int,
 	workersCreator WorkersWithFuncCreator,
 	fn func(func()),
 	opt ...Option,
+) (*PoolWithFunc, error) {
+	return NewPoolWithFuncWorkerFactory(cap, workersCreator, WorkerWithFuncCreator, fn, opt...)
+}
+
+// 使用自定义worker工厂创建池子，可替换为带监控或绑定线程等自定义的Worker实现。
+// 自定义worker需遵循与默认worker相同的约定：任务结束后调用PutReady，退出时调用PutCache。
+func NewPoolWithFuncWorkerFactory(
+	cap int,
+	workersCreator WorkersWithFuncCreator,
+	workerFactory WorkerWithFuncFactory,
+	fn func(func()),
+	opt ...Option,
 ) (*PoolWithFunc, error) {
 	workers, _ := workersCreator(cap)
 	opts := NewOptions(opt...)
-	scheduler := NewScheduler(int32(cap), workers, WorkerWithFuncCreator, fn, opts)
+	factory := workerFactory
+	if opts.LockOSThread {
+		factory = scheduler_func.LockOSThread(workerFactory)
+	}
+	scheduler := NewScheduler(int32(cap), workers, factory, fn, opts)
+	if opts.LockOSThread {
+		// 用工厂创建一个worker检查能否绑定线程，避免自定义worker静默忽略该选项
+		if _, ok := workerFactory(scheduler).(scheduler_func.ThreadLocker); !ok {
+			return nil, errors.ErrorLockOSThreadUnsupported
+		}
+	}
 
 	// New pool
 	p := &PoolWithFunc{
@@ -205,10 +434,16 @@ func NewPoolWithFunc(
 		scheduler:      scheduler,
 		clockCtxCancel: ctx.NewContextWithCancel(context.Background()),
 		clearCtxCancel: ctx.NewContextWithCancel(context.Background()),
+		runCtxCancel:   ctx.NewContextWithCancel(context.Background()),
 	}
+	scheduler.SetRunContext(p.runCtxCancel.Ctx)
 	p.Open()
+	if opts.PreAlloc > 0 {
+		p.Prewarm(opts.PreAlloc)
+	}
 	//p.clock(500 * time.Millisecond)
 	p.clear(p.options.ExpiryDuration)
+	p.watchdog()
 	return p, nil
 }
 
@@ -1,10 +1,21 @@
 package turbopool
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"runtime/trace"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/gaohao-creator/turbopool/clock/clocktest"
+	"github.com/gaohao-creator/turbopool/errors"
+	"github.com/gaohao-creator/turbopool/scheduler_func"
+	"github.com/gaohao-creator/turbopool/tracing"
+	"github.com/gaohao-creator/turbopool/tracing/tracingtest"
 )
 
 func TestPoolWithFunc(t *testing.T) {
@@ -48,3 +59,533 @@ func TestPoolWithFunc_2(t *testing.T) {
 	wg.Wait()
 	fmt.Println("done")
 }
+
+func TestPoolWithFuncClearExpiredWithFakeClock(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(5, WithExpiryDuration(time.Second), WithClock(fc))
+	defer pool.Release()
+
+	// 让5个worker同时运行，任务结束后全部回到就绪队列
+	gate := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(5)
+	for j := 0; j < 5; j++ {
+		_ = pool.Submit(func() {
+			<-gate
+			wg.Done()
+		})
+	}
+	close(gate)
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+
+	// 时钟未推进，空闲worker不会被清理
+	if running := pool.Running(); running != 5 {
+		t.Fatalf("running = %d, want 5", running)
+	}
+
+	waitFor(t, func() bool {
+		if pool.Running() == 0 {
+			return true
+		}
+		fc.Advance(2 * time.Second)
+		return false
+	}, "idle workers not reaped")
+}
+
+func TestPoolWithFuncMinIdleWorkers(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(
+		5,
+		WithExpiryDuration(time.Second),
+		WithClock(fc),
+		WithPreAlloc(5),
+		WithMinIdleWorkers(2),
+	)
+	defer pool.Release()
+
+	if running := pool.Running(); running != 5 {
+		t.Fatalf("running after prealloc = %d, want 5", running)
+	}
+
+	waitFor(t, func() bool {
+		if pool.Running() <= 2 {
+			return true
+		}
+		fc.Advance(2 * time.Second)
+		return false
+	}, "idle workers not reaped")
+
+	// 再次推进时钟，保留的空闲worker不会被清理
+	fc.Advance(2 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if running := pool.Running(); running != 2 {
+		t.Fatalf("running = %d, want 2", running)
+	}
+}
+
+func TestPoolWithFuncWorkerRecycle(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(
+		1,
+		WithClock(fc),
+		WithWorkerMaxTasks(2),
+		WithWorkerMaxLifetime(time.Minute),
+	)
+	defer pool.Release()
+
+	submit := func(task func()) {
+		done := make(chan struct{})
+		_ = pool.Submit(func() {
+			task()
+			close(done)
+		})
+		<-done
+	}
+
+	// 第二个任务后worker达到任务数上限
+	submit(func() {})
+	submit(func() {})
+	// 任务执行期间时钟超过存活时长上限
+	submit(func() { fc.Advance(2 * time.Minute) })
+
+	waitFor(t, func() bool {
+		return pool.RecycledByTasks() == 1 && pool.RecycledByLifetime() == 1 && pool.Running() == 0
+	}, "workers not recycled by tasks and lifetime")
+}
+
+func TestPoolWithFuncWorkerFactory(t *testing.T) {
+	var created atomic.Int32
+	factory := func(s scheduler_func.Scheduler) scheduler_func.WorkerWithFunc {
+		created.Add(1)
+		return scheduler_func.NewWorkerWithFunc(s)
+	}
+	pool, _ := NewPoolWithFuncWorkerFactory(5, scheduler_func.NewWorkersStackWithFunc, factory, func(task func()) {
+		task()
+	}, WithExpiryDuration(10*time.Second))
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	wg.Add(20)
+	for j := 0; j < 20; j++ {
+		_ = pool.Submit(wg.Done)
+	}
+	wg.Wait()
+	if n := created.Load(); n < 1 || n > 5 {
+		t.Fatalf("created = %d, want 1..5", n)
+	}
+}
+
+// 不支持绑定线程的自定义worker不能静默忽略 WithLockOSThread
+func TestPoolWithFuncLockOSThreadUnsupported(t *testing.T) {
+	type plainWorker struct{ scheduler_func.WorkerWithFunc }
+	factory := func(s scheduler_func.Scheduler) scheduler_func.WorkerWithFunc {
+		return plainWorker{scheduler_func.NewWorkerWithFunc(s)}
+	}
+	_, err := NewPoolWithFuncWorkerFactory(1, scheduler_func.NewWorkersStackWithFunc, factory, func(task func()) {
+		task()
+	}, WithLockOSThread(true))
+	if !errors.Is(err, errors.ErrorLockOSThreadUnsupported) {
+		t.Fatalf("err = %v, want ErrorLockOSThreadUnsupported", err)
+	}
+}
+
+func TestPoolWithFuncHistograms(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithClock(fc), WithHistograms(true))
+	defer pool.Release()
+
+	for j := 0; j < 10; j++ {
+		done := make(chan struct{})
+		_ = pool.Submit(func() {
+			fc.Advance(5 * time.Millisecond) // 任务执行耗时5ms
+			close(done)
+		})
+		<-done
+	}
+
+	waitFor(t, func() bool { return pool.Stats().ExecLatency.Count == 10 }, "exec latency count never reached 10")
+	stats := pool.Stats()
+	if stats.WaitLatency.Count != 10 {
+		t.Fatalf("wait latency count = %d, want 10", stats.WaitLatency.Count)
+	}
+	// 5ms 落在 [4.19ms, 8.39ms) 的桶中
+	if want := time.Duration(1) << 23; stats.ExecLatency.P50 != want || stats.ExecLatency.P99 != want {
+		t.Fatalf("exec p50 = %v, p99 = %v, want %v", stats.ExecLatency.P50, stats.ExecLatency.P99, want)
+	}
+	if stats.ExecLatency.Sum != 50*time.Millisecond {
+		t.Fatalf("exec latency sum = %v, want 50ms", stats.ExecLatency.Sum)
+	}
+}
+
+func TestPoolWithFuncHooks(t *testing.T) {
+	var (
+		lock                             sync.Mutex
+		workerStarts, taskStarts, blocks int
+		unblocks, rejects, panickedEnds  int
+		taskEnds                         int
+		exits                            = map[WorkerExitReason]int{}
+	)
+	record := func(f func()) {
+		lock.Lock()
+		defer lock.Unlock()
+		f()
+	}
+	pool, _ := NewPoolWithFuncDefaultHandler(
+		1,
+		WithMaxBlockingTasks(2), // 计数包含本次提交，最多允许1个阻塞等待
+		WithPanicHandler(func(any) {}),
+		WithHooks(Hooks{
+			OnWorkerStart: func() { record(func() { workerStarts++ }) },
+			OnWorkerExit:  func(r WorkerExitReason) { record(func() { exits[r]++ }) },
+			OnTaskStart:   func() { record(func() { taskStarts++ }) },
+			OnTaskEnd: func(d time.Duration, panicked bool) {
+				record(func() {
+					taskEnds++
+					if panicked {
+						panickedEnds++
+					}
+				})
+			},
+			OnReject:  func(error) { record(func() { rejects++ }) },
+			OnBlock:   func() { record(func() { blocks++ }) },
+			OnUnblock: func() { record(func() { unblocks++ }) },
+		}),
+	)
+	defer pool.Release()
+
+	gate := make(chan struct{})
+	_ = pool.Submit(func() { <-gate })
+	blockedDone := make(chan struct{})
+	go func() {
+		_ = pool.Submit(func() { close(blockedDone) })
+	}()
+	waitFor(t, func() bool { return pool.Waiting() == 1 }, "submitter not blocked")
+	if err := pool.Submit(func() {}); err == nil {
+		t.Fatal("submit over max blocking tasks should fail")
+	}
+	close(gate)
+	<-blockedDone
+
+	_ = pool.Submit(func() { panic("boom") })
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return panickedEnds == 1
+	}, "task panic not reported")
+
+	lock.Lock()
+	defer lock.Unlock()
+	if workerStarts < 1 || taskStarts != 3 || taskEnds != 3 || panickedEnds != 1 {
+		t.Fatalf("worker starts = %d, task starts = %d, task ends = %d, panicked = %d",
+			workerStarts, taskStarts, taskEnds, panickedEnds)
+	}
+	if blocks != 1 || unblocks != 1 || rejects != 1 {
+		t.Fatalf("blocks = %d, unblocks = %d, rejects = %d", blocks, unblocks, rejects)
+	}
+	if n := exits[WorkerExitPanicked]; n != 0 {
+		t.Fatalf("worker exited %d times after task panic, want 0", n)
+	}
+}
+
+func TestPoolWithFuncSubmitContextTracing(t *testing.T) {
+	recorder := tracingtest.NewRecorder()
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithTracer(recorder))
+	defer pool.Release()
+
+	ctx, root := recorder.Start(context.Background(), "request")
+	done := make(chan struct{})
+	if err := pool.SubmitContext(ctx, func() { close(done) }); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	root.End()
+
+	waitFor(t, func() bool { return len(recorder.Ended(tracing.SpanExecute)) == 1 }, "execute span not ended")
+	rootID := recorder.Ended("request")[0].ID
+	wait := recorder.Ended(tracing.SpanWait)
+	execute := recorder.Ended(tracing.SpanExecute)
+	if len(wait) != 1 || wait[0].ParentID != rootID || execute[0].ParentID != rootID {
+		t.Fatalf("spans not linked to submitter span: %+v", recorder.Spans())
+	}
+}
+
+func TestPoolWithFuncSubmitContextTimeout(t *testing.T) {
+	pool, _ := NewPoolWithFuncDefaultHandler(1)
+	defer pool.Release()
+
+	gate := make(chan struct{})
+	defer close(gate)
+	_ = pool.Submit(func() { <-gate })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.SubmitContext(ctx, func() {}); !errors.Is(err, errors.ErrorSubmitTaskTimeout) {
+		t.Fatalf("err = %v, want %v", err, errors.ErrorSubmitTaskTimeout)
+	}
+	if stats := pool.Stats(); stats.TimedOut != 1 || stats.Waiting != 0 {
+		t.Fatalf("timed out = %d, waiting = %d, want 1, 0", stats.TimedOut, stats.Waiting)
+	}
+}
+
+func TestPoolWithFuncWatchdog(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	reports := make(chan SlowTask, 4)
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithClock(fc), WithWatchdog(time.Second, 100*time.Millisecond, func(task SlowTask) {
+		reports <- task
+	}))
+	defer pool.Release()
+
+	started := make(chan struct{})
+	gate := make(chan struct{})
+	defer close(gate)
+	_ = pool.SubmitWithLabels(func() {
+		close(started)
+		<-gate
+	}, "job", "resize")
+	<-started
+
+	// 未超过阈值时不报告
+	fc.Advance(500 * time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case task := <-reports:
+		t.Fatalf("unexpected report before threshold: %+v", task)
+	default:
+	}
+
+	var task SlowTask
+	waitFor(t, func() bool {
+		fc.Advance(time.Second)
+		select {
+		case task = <-reports:
+			return true
+		case <-time.After(time.Millisecond):
+			return false
+		}
+	}, "slow task not reported")
+	if task.Elapsed < time.Second || task.GoroutineID == 0 || !strings.Contains(task.Stack, "TestPoolWithFuncWatchdog") ||
+		!strings.Contains(task.Task, "TestPoolWithFuncWatchdog") || task.Labels["job"] != "resize" {
+		t.Fatalf("bad report: %+v", task)
+	}
+
+	// 同一个任务只报告一次
+	fc.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case task := <-reports:
+		t.Fatalf("task reported twice: %+v", task)
+	default:
+	}
+}
+
+func TestPoolWithFuncDebug(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithClock(fc), WithPreAlloc(2))
+	defer pool.Release()
+
+	fc.Advance(3 * time.Second)
+	debug := pool.Debug()
+	if debug.State != "opened" || len(debug.ReadyWorkers) != 2 || debug.RunningTasks != nil || len(debug.Waiters) != 0 {
+		t.Fatalf("debug = %+v", debug)
+	}
+	for _, w := range debug.ReadyWorkers {
+		if w.Idle != 3*time.Second {
+			t.Fatalf("idle = %v, want 3s", w.Idle)
+		}
+	}
+}
+
+func TestPoolWithFuncRuntimeTrace(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("runtime trace already running")
+	}
+	pool, _ := NewPoolWithFuncDefaultHandler(1, WithName("orders"), WithRuntimeTrace(true))
+	defer pool.Release()
+
+	buf := &bytes.Buffer{}
+	if err := trace.Start(buf); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		_ = pool.Submit(func() { wg.Done() })
+	}
+	wg.Wait()
+	trace.Stop()
+
+	// trace中包含任务、等待与执行区域以及worker生命周期日志
+	for _, want := range []string{TraceTask, TraceRegionWait, TraceRegionExecute, TraceCategory} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Fatalf("trace missing %q", want)
+		}
+	}
+}
+
+func TestPoolWithFuncPanicHandlerV2(t *testing.T) {
+	infos := make(chan PanicInfo, 1)
+	var called atomic.Bool
+	pool, _ := NewPoolWithFuncDefaultHandler(1, WithName("orders"),
+		WithPanicHandler(func(any) { called.Store(true) }),
+		WithPanicHandlerV2(func(info PanicInfo) { infos <- info }))
+	defer pool.Release()
+
+	before := time.Now()
+	_ = pool.Submit(func() { panic("boom") })
+	info := <-infos
+	if info.Value != "boom" || info.PoolName != "orders" || info.Task == nil || info.StartedAt.Before(before) {
+		t.Fatalf("info = %+v", info)
+	}
+	if !strings.Contains(string(info.Stack), "TestPoolWithFuncPanicHandlerV2") {
+		t.Fatalf("stack does not contain the panicking task:\n%s", info.Stack)
+	}
+	if called.Load() {
+		t.Fatal("PanicHandler called although PanicHandlerV2 is set")
+	}
+}
+
+func TestPoolWithFuncPanicKeepsWorker(t *testing.T) {
+	panics := make(chan any, 1)
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithPanicHandler(func(p any) { panics <- p }))
+	defer pool.Release()
+
+	// 任务panic后worker回到就绪队列继续复用，不会创建新的worker
+	for i := 0; i < 5; i++ {
+		_ = pool.Submit(func() { panic(i) })
+		if p := <-panics; p != i {
+			t.Fatalf("panic = %v, want %d", p, i)
+		}
+		waitFor(t, func() bool { return pool.Free() == 1 && pool.Stats().Idle == 1 }, "worker not back to ready")
+		if running := pool.Running(); running != 1 {
+			t.Fatalf("running = %d, want 1", running)
+		}
+	}
+	done := make(chan struct{})
+	_ = pool.Submit(func() { close(done) })
+	<-done
+	stats := pool.Stats()
+	if stats.WorkersCreated != 1 || stats.Panicked != 5 || stats.Running != 1 {
+		t.Fatalf("created = %d, panicked = %d, running = %d, want 1, 5, 1",
+			stats.WorkersCreated, stats.Panicked, stats.Running)
+	}
+}
+
+func TestPoolWithFuncSubmitError(t *testing.T) {
+	pool, _ := NewPoolWithFuncDefaultHandler(1, WithName("orders"), WithNonblocking(true))
+
+	gate := make(chan struct{})
+	_ = pool.Submit(func() { <-gate })
+	err := pool.Submit(func() {})
+	close(gate)
+	var submitErr *errors.SubmitError
+	if !errors.As(err, &submitErr) {
+		t.Fatalf("err = %v, want *SubmitError", err)
+	}
+	if submitErr.Reason != errors.SubmitReasonFull || submitErr.PoolName != "orders" || submitErr.Cap != 1 {
+		t.Fatalf("submit error = %+v", submitErr)
+	}
+	if !errors.Is(err, errors.ErrorSchedulerIsFull) || !errors.Is(err, errors.ErrorSubmitTaskFail) {
+		t.Fatalf("err = %v does not wrap the sentinels", err)
+	}
+
+	pool.Release()
+	err = pool.Submit(func() {})
+	if !errors.As(err, &submitErr) || submitErr.Reason != errors.SubmitReasonPoolClosed || !errors.Is(err, errors.ErrorPoolClosed) {
+		t.Fatalf("err = %v after release", err)
+	}
+}
+
+func TestPoolWithFuncBreaker(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(1, WithClock(fc),
+		WithPanicHandler(func(any) {}),
+		WithBreaker(BreakerConfig{FailureRate: 0.5, MinRequests: 4, Window: time.Minute, Cooldown: time.Second}))
+	defer pool.Release()
+
+	// 返回错误与panic都计入失败
+	_ = pool.SubmitWithError(func() error { return nil })
+	_ = pool.SubmitWithError(func() error { return fmt.Errorf("failed") })
+	_ = pool.Submit(func() { panic("boom") })
+	_ = pool.SubmitWithError(func() error { return nil })
+	waitFor(t, func() bool { return pool.Stats().BreakerState == BreakerOpen }, "breaker not opened")
+	if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorBreakerOpen) {
+		t.Fatalf("err = %v, want breaker open", err)
+	}
+
+	// 冷却后半开，探测失败重新打开
+	fc.Advance(time.Second)
+	if err := pool.SubmitWithError(func() error { return fmt.Errorf("still failing") }); err != nil {
+		t.Fatalf("probe submit: %v", err)
+	}
+	waitFor(t, func() bool { return pool.Stats().BreakerOpens == 2 }, "breaker not reopened")
+	if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorBreakerOpen) {
+		t.Fatalf("err = %v, want breaker open", err)
+	}
+}
+
+func TestPoolWithFuncReboot(t *testing.T) {
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(2, WithExpiryDuration(time.Second), WithClock(fc))
+	defer pool.Release()
+
+	for cycle := 0; cycle < 3; cycle++ {
+		var executed atomic.Int32
+		var wg sync.WaitGroup
+		wg.Add(4)
+		for i := 0; i < 4; i++ {
+			if err := pool.Submit(func() {
+				executed.Add(1)
+				wg.Done()
+			}); err != nil {
+				t.Fatalf("cycle %d: submit: %v", cycle, err)
+			}
+		}
+		wg.Wait()
+
+		// 重启后过期清理继续工作
+		waitFor(t, func() bool {
+			if pool.Running() == 0 {
+				return true
+			}
+			fc.Advance(2 * time.Second)
+			return false
+		}, fmt.Sprintf("cycle %d: idle workers not reaped", cycle))
+
+		// 每次释放都会重新通知完成
+		if err := pool.ReleaseWithTimeout(time.Second); err != nil {
+			t.Fatalf("cycle %d: release: %v", cycle, err)
+		}
+		if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorPoolClosed) {
+			t.Fatalf("cycle %d: submit after release: %v", cycle, err)
+		}
+		pool.Reboot()
+		if pool.Closed() || !pool.Opened() {
+			t.Fatalf("cycle %d: pool not reopened", cycle)
+		}
+	}
+}
+
+func TestPoolWithFuncRebootWithRunningTasks(t *testing.T) {
+	pool, _ := NewPoolWithFuncDefaultHandler(2)
+	defer pool.Release()
+
+	// 释放时仍有任务在执行，最后一个worker退出后才通知完成
+	gate := make(chan struct{})
+	_ = pool.Submit(func() { <-gate })
+	pool.Release()
+	select {
+	case <-pool.scheduler.Done():
+		t.Fatal("done closed while a task is running")
+	default:
+	}
+	close(gate)
+	pool.Wait()
+
+	pool.Reboot()
+	done := make(chan struct{})
+	if err := pool.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("submit after reboot: %v", err)
+	}
+	<-done
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package turbopool
+
+import (
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// 单个worker绑定线程后，所有任务都应在同一线程上执行
+func TestPoolLockOSThread(t *testing.T) {
+	var tids sync.Map
+	var wg sync.WaitGroup
+	pool, _ := NewPoolDefaultWorkers(1, func(task int) {
+		defer wg.Done()
+		tids.Store(syscall.Gettid(), struct{}{})
+		time.Sleep(time.Millisecond) // 让出执行权，未绑定时goroutine可能被调度到其他线程
+	}, WithLockOSThread(true), WithExpiryDuration(10*time.Second))
+	defer pool.Release()
+
+	wg.Add(50)
+	for j := 0; j < 50; j++ {
+		_ = pool.Submit(j)
+	}
+	wg.Wait()
+	count := 0
+	tids.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Fatalf("tasks ran on %d threads, want 1", count)
+	}
+}
+
+func TestPoolWithFuncLockOSThread(t *testing.T) {
+	var tids sync.Map
+	var wg sync.WaitGroup
+	pool, _ := NewPoolWithFuncDefaultHandler(1, WithLockOSThread(true), WithExpiryDuration(10*time.Second))
+	defer pool.Release()
+
+	wg.Add(50)
+	for j := 0; j < 50; j++ {
+		_ = pool.Submit(func() {
+			defer wg.Done()
+			tids.Store(syscall.Gettid(), struct{}{})
+			time.Sleep(time.Millisecond)
+		})
+	}
+	wg.Wait()
+	count := 0
+	tids.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Fatalf("tasks ran on %d threads, want 1", count)
+	}
+}
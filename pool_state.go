@@ -0,0 +1,51 @@
+package turbopool
+
+import (
+	"log/slog"
+
+	"github.com/gaohao-creator/turbopool/scheduler_generic"
+)
+
+// worker启动时创建状态（解析器、缓冲区、DB statement等）
+type WorkerInit[S any] func() (S, error)
+
+// worker退出时释放状态
+type WorkerClose[S any] func(S)
+
+// 带worker状态的池子，每个worker持有一份由WorkerInit创建的状态，并在它执行的所有任务间复用
+type PoolWithState[S, T any] struct {
+	*Pool[T]
+}
+
+// 创建带worker状态的池子。
+// init在worker启动时执行，失败时错误写入结构化日志或Logger，worker会在下一个任务前重试；重试仍失败时任务不执行，
+// 计入 Stats().Failed 并交给panic处理器报告（PanicInfo.Value 包装 ErrorWorkerInit）；
+// fn以(状态, 任务)处理任务；close在worker结束（Finish、过期清理、Release）时释放状态，可为nil。
+func NewPoolWithState[S, T any](
+	cap int,
+	init WorkerInit[S],
+	fn func(S, T),
+	close WorkerClose[S],
+	opt ...Option,
+) (*PoolWithState[S, T], error) {
+	opts := NewOptions(opt...)
+	workerInit := func() (S, error) {
+		state, err := init()
+		if err != nil {
+			switch {
+			case opts.Slog != nil:
+				opts.logAttrs(opts.SlogLevels.WorkerInit, "worker init failed", slog.Any("error", err))
+			case opts.Logger != nil:
+				opts.Logger.Printf("worker init failed: %v\n", err)
+			}
+		}
+		return state, err
+	}
+	workerCreator := scheduler_generic.NewWorkerWithState(workerInit, fn, close)
+	// 任务由worker自身的状态处理函数执行，调度器不需要handler
+	p, err := newPool(cap, scheduler_generic.NewWorkersStack[T], workerCreator, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PoolWithState[S, T]{Pool: p}, nil
+}
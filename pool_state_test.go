@@ -0,0 +1,128 @@
+package turbopool
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	poolerrors "github.com/gaohao-creator/turbopool/errors"
+)
+
+type testLogger struct {
+	lines atomic.Int32
+}
+
+func (l *testLogger) Printf(format string, args ...any) {
+	l.lines.Add(1)
+}
+
+func TestPoolWithState(t *testing.T) {
+	var inits, closes atomic.Int32
+	var wg sync.WaitGroup
+	pool, _ := NewPoolWithState(2, func() (*[]int, error) {
+		inits.Add(1)
+		return &[]int{}, nil
+	}, func(buf *[]int, task int) {
+		defer wg.Done()
+		*buf = append(*buf, task) // 同一worker内状态复用，无需加锁
+	}, func(buf *[]int) {
+		closes.Add(1)
+	}, WithExpiryDuration(10*time.Second))
+
+	wg.Add(20)
+	for j := 0; j < 20; j++ {
+		if err := pool.Submit(j); err != nil {
+			fmt.Println(err)
+		}
+	}
+	wg.Wait()
+	if n := inits.Load(); n < 1 || n > 2 {
+		t.Fatalf("inits = %d, want 1..2", n)
+	}
+
+	pool.Release()
+	waitFor(t, func() bool { return closes.Load() == inits.Load() }, "not every initialized state was closed")
+}
+
+func TestPoolWithStateInitError(t *testing.T) {
+	var inits, closes atomic.Int32
+	logger := &testLogger{}
+	done := make(chan int, 1)
+	pool, _ := NewPoolWithState(1, func() (int, error) {
+		// 第一次初始化失败，之后成功
+		if inits.Add(1) == 1 {
+			return 0, errors.New("init fail")
+		}
+		return 42, nil
+	}, func(state int, task int) {
+		done <- state
+	}, func(state int) {
+		closes.Add(1)
+	}, WithLogger(logger), WithPreAlloc(1))
+	defer pool.Release()
+
+	// 预热的worker初始化失败，任务到来时重试
+	waitFor(t, func() bool { return logger.lines.Load() == 1 }, "init error not logged")
+	_ = pool.Submit(1)
+	if state := <-done; state != 42 {
+		t.Fatalf("state = %d, want 42", state)
+	}
+	if n := closes.Load(); n != 0 {
+		t.Fatalf("closes = %d, want 0", n)
+	}
+}
+
+func TestPoolWithStateInitFailure(t *testing.T) {
+	reported := make(chan PanicInfo, 1)
+	var handled atomic.Int32
+	buf := &syncBuffer{}
+	pool, _ := NewPoolWithState(1, func() (int, error) {
+		return 0, errors.New("init fail")
+	}, func(state int, task int) {
+		handled.Add(1)
+	}, nil, WithPanicHandlerV2(func(info PanicInfo) {
+		reported <- info
+	}), WithSlog(slog.New(slog.NewJSONHandler(buf, nil))))
+	defer pool.Release()
+
+	// 启动和重试都失败，任务不执行并报告
+	if err := pool.Submit(7); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	info := <-reported
+	err, _ := info.Value.(error)
+	if !errors.Is(err, poolerrors.ErrorWorkerInit) || info.Task != 7 {
+		t.Fatalf("reported = %+v", info)
+	}
+	// 初始化失败先于任务失败报告写入结构化日志
+	if record := buf.find(t, "worker init failed"); record == nil || record["level"] != "WARN" {
+		t.Fatalf("init failure record = %v", record)
+	}
+	pool.ReleaseWithWait()
+	if s := pool.Stats(); s.Failed != 1 || s.Completed != 0 || handled.Load() != 0 {
+		t.Fatalf("failed = %d, completed = %d, handled = %d", s.Failed, s.Completed, handled.Load())
+	}
+}
+
+// 暂停期间worker持有的任务不因初始化失败而提前失败，Drain 时原样取回
+func TestPoolWithStateInitFailureWhilePaused(t *testing.T) {
+	pool, _ := NewPoolWithState(1, func() (int, error) {
+		return 0, errors.New("init fail")
+	}, func(state int, task int) {}, nil, WithPanicHandlerV2(func(PanicInfo) {}))
+
+	pool.Pause()
+	if err := pool.Submit(7); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	pending := pool.Drain()
+	if len(pending) != 1 || pending[0] != 7 {
+		t.Fatalf("pending = %v, want [7]", pending)
+	}
+	if s := pool.Stats(); s.Failed != 0 || s.Dropped != 1 {
+		t.Fatalf("failed = %d, dropped = %d, want 0, 1", s.Failed, s.Dropped)
+	}
+}
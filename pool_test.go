@@ -0,0 +1,656 @@
+package turbopool
+
+import (
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/errors"
+	"github.com/gaohao-creator/turbopool/tracing"
+	"github.com/gaohao-creator/turbopool/tracing/tracingtest"
+)
+
+// Pool[func()] 与 PoolWithFunc 共有的方法，行为一致的测试用同一张表覆盖两种池子
+type testPool interface {
+	Submit(task func()) error
+	SubmitContext(taskCtx context.Context, task func()) error
+	SubmitWithLabels(task func(), labels ...string) error
+	Release()
+	ReleaseWithWait()
+	ReleaseContext(releaseCtx context.Context, drop bool) (ReleaseReport[func()], error)
+	Drain() []func()
+	Reboot()
+	ReleaseAndCollect(t time.Duration) ([]func(), error)
+	Wait()
+	Context() context.Context
+	Close()
+	Open()
+	Closed() bool
+	Pause()
+	Resume()
+	Paused() bool
+	Running() int32
+	Working() int32
+	Waiting() int32
+	Cap() int32
+	Scale(cap int)
+	Stats() Stats
+	Debug() Debug
+}
+
+var testPools = []struct {
+	name string
+	new  func(cap int, opts ...Option) testPool
+}{
+	{"Pool", func(cap int, opts ...Option) testPool {
+		p, _ := NewPoolDefaultHandler(cap, opts...)
+		return p
+	}},
+	{"PoolWithFunc", func(cap int, opts ...Option) testPool {
+		p, _ := NewPoolWithFuncDefaultHandler(cap, opts...)
+		return p
+	}},
+}
+
+// 轮询等待cond成立，超过1秒仍不成立则以msg失败；cond中可以顺带推进假时钟
+func waitFor(t *testing.T, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// 断言ch在短时间内没有关闭，用于确认调用仍在阻塞
+func assertBlocked(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatalf("%s returned early", what)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestPoolsCloseOpen(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(1)
+			defer pool.Release()
+
+			// 关闭时仍在执行任务的worker结束后照常回到就绪栈，Close 不能拆掉它
+			first := make(chan struct{})
+			_ = pool.Submit(func() { <-first })
+			pool.Close()
+			if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorPoolClosed) {
+				t.Fatalf("submit after close: %v", err)
+			}
+			close(first)
+			waitFor(t, func() bool { return pool.Stats().Completed == 1 }, "first task not completed")
+			if pool.Running() != 1 {
+				t.Fatalf("running = %d after close, want the worker kept", pool.Running())
+			}
+			pool.Open()
+			if pool.Closed() {
+				t.Fatal("pool still closed after open")
+			}
+
+			started, gate := make(chan struct{}), make(chan struct{})
+			if err := pool.Submit(func() {
+				close(started)
+				<-gate
+			}); err != nil {
+				t.Fatalf("submit after open: %v", err)
+			}
+			<-started
+
+			// 重新打开后 Wait 仍要等执行中的任务结束
+			pool.Release()
+			waited := make(chan struct{})
+			go func() {
+				pool.Wait()
+				close(waited)
+			}()
+			assertBlocked(t, waited, "Wait")
+			close(gate)
+			<-waited
+		})
+	}
+}
+
+// 超时释放后仍在 Wait 的调用在重启时返回，不会等待已被替换的完成信号
+func TestPoolsRebootWakesWaiters(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(1)
+			defer pool.Release()
+
+			started, gate := make(chan struct{}), make(chan struct{})
+			defer close(gate)
+			_ = pool.Submit(func() {
+				close(started)
+				<-gate
+			})
+			<-started
+			releaseCtx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if _, err := pool.ReleaseContext(releaseCtx, false); !errors.Is(err, errors.ErrorPoolReleaseTimeout) {
+				t.Fatalf("release: %v", err)
+			}
+
+			waited := make(chan struct{})
+			go func() {
+				pool.Wait()
+				close(waited)
+			}()
+			assertBlocked(t, waited, "Wait")
+			pool.Reboot()
+			<-waited
+		})
+	}
+}
+
+func TestPoolsHooksOutsideLock(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			var pool testPool
+			waiters := make(chan int, 1)
+			rejected := make(chan error, 1)
+			pool = tp.new(1, WithHooks(Hooks{
+				// 钩子中读取池子状态不能死锁
+				OnBlock:  func() { waiters <- len(pool.Debug().Waiters) },
+				OnReject: func(err error) { rejected <- err },
+			}))
+			defer pool.Release()
+
+			gate := make(chan struct{})
+			_ = pool.Submit(func() { <-gate })
+			submitted := make(chan error, 1)
+			go func() { submitted <- pool.Submit(func() {}) }()
+			if n := <-waiters; n != 1 {
+				t.Fatalf("waiters seen by OnBlock = %d, want 1", n)
+			}
+			close(gate)
+			if err := <-submitted; err != nil {
+				t.Fatalf("blocked submit: %v", err)
+			}
+
+			// 池子关闭后的拒绝同样计数并触发 OnReject
+			pool.Close()
+			if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorPoolClosed) {
+				t.Fatalf("submit after close: %v", err)
+			}
+			if err := <-rejected; !errors.Is(err, errors.ErrorPoolClosed) {
+				t.Fatalf("OnReject got %v", err)
+			}
+			if n := pool.Stats().Rejected; n != 1 {
+				t.Fatalf("rejected = %d, want 1", n)
+			}
+		})
+	}
+}
+
+// 熔断器状态变化钩子中读取 Stats 不能死锁
+func TestPoolsBreakerChangeReadsStats(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			var pool testPool
+			states := make(chan BreakerState, 1)
+			pool = tp.new(1,
+				WithPanicHandler(func(any) {}),
+				WithBreaker(BreakerConfig{MinRequests: 1}),
+				WithHooks(Hooks{OnBreakerChange: func(from, to BreakerState) {
+					states <- pool.Stats().BreakerState
+				}}))
+			defer pool.Release()
+
+			_ = pool.Submit(func() { panic("boom") })
+			select {
+			case state := <-states:
+				if state != BreakerOpen {
+					t.Fatalf("state seen by hook = %v, want open", state)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("OnBreakerChange deadlocked reading Stats")
+			}
+		})
+	}
+}
+
+func TestPoolsScale(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(1)
+			defer pool.Release()
+
+			gate := make(chan struct{})
+			defer close(gate)
+			_ = pool.Submit(func() { <-gate })
+			submitted := make(chan struct{})
+			go func() {
+				_ = pool.Submit(func() {})
+				close(submitted)
+			}()
+			assertBlocked(t, submitted, "Submit on a full pool")
+
+			// 扩容后阻塞的提交方立即拿到新的worker
+			pool.Scale(2)
+			<-submitted
+			if c := pool.Cap(); c != 2 {
+				t.Fatalf("cap = %d, want 2", c)
+			}
+		})
+	}
+}
+
+func TestPoolsPprofLabels(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(1, WithName("orders"), WithPprofLabels(true))
+			defer pool.Release()
+
+			started := make(chan struct{})
+			gate := make(chan struct{})
+			_ = pool.SubmitWithLabels(func() {
+				close(started)
+				<-gate
+			}, "task", "resize")
+			<-started
+
+			// 执行期间worker goroutine带有池子名称和提交方的标签
+			buf := &strings.Builder{}
+			_ = pprof.Lookup("goroutine").WriteTo(buf, 1)
+			close(gate)
+			for _, want := range []string{`"turbopool.pool":"orders"`, `"task":"resize"`} {
+				if !strings.Contains(buf.String(), want) {
+					t.Fatalf("goroutine profile missing label %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolsPauseResume(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			blocked := make(chan struct{}, 2)
+			pool := tp.new(2, WithHooks(Hooks{OnBlock: func() { blocked <- struct{}{} }}))
+			defer pool.Release()
+
+			pool.Pause()
+			if !pool.Paused() || pool.Debug().State != "paused" {
+				t.Fatal("pool not paused")
+			}
+
+			// 暂停期间提交照常被接受：2个分配到worker，其余阻塞等待，但都不会开始执行
+			var executed atomic.Int32
+			var wg sync.WaitGroup
+			wg.Add(4)
+			task := func() {
+				executed.Add(1)
+				wg.Done()
+			}
+			for i := 0; i < 2; i++ {
+				if err := pool.Submit(task); err != nil {
+					t.Fatalf("submit while paused: %v", err)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				go func() {
+					if err := pool.Submit(task); err != nil {
+						t.Errorf("blocked submit while paused: %v", err)
+					}
+				}()
+			}
+			<-blocked
+			<-blocked
+			if n := executed.Load(); n != 0 {
+				t.Fatalf("executed = %d while paused, want 0", n)
+			}
+
+			pool.Resume()
+			wg.Wait()
+			if pool.Paused() {
+				t.Fatal("pool still paused after resume")
+			}
+		})
+	}
+}
+
+// 默认配置下提交到执行结束的路径不分配内存
+func TestPoolsSubmitAllocs(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(1)
+			defer pool.Release()
+
+			done := make(chan struct{}, 1)
+			task := func() { done <- struct{}{} }
+			for _, submit := range []struct {
+				name string
+				fn   func() error
+			}{
+				{"Submit", func() error { return pool.Submit(task) }},
+				{"SubmitContext", func() error { return pool.SubmitContext(context.Background(), task) }},
+			} {
+				allocs := testing.AllocsPerRun(1000, func() {
+					if err := submit.fn(); err != nil {
+						t.Fatal(err)
+					}
+					<-done
+				})
+				if allocs != 0 {
+					t.Fatalf("%s allocs = %v, want 0", submit.name, allocs)
+				}
+			}
+		})
+	}
+}
+
+// 非阻塞池子暂停时，每个worker持有一个待执行任务，worker用完后提交返回已满
+func TestPoolsPauseNonblocking(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(2, WithNonblocking(true))
+			defer pool.Release()
+
+			pool.Pause()
+			done := make(chan struct{}, 2)
+			for i := 0; i < 2; i++ {
+				if err := pool.Submit(func() { done <- struct{}{} }); err != nil {
+					t.Fatalf("submit %d while paused: %v", i, err)
+				}
+			}
+			if err := pool.Submit(func() {}); !errors.Is(err, errors.ErrorSchedulerIsFull) {
+				t.Fatalf("submit beyond capacity while paused: %v", err)
+			}
+			select {
+			case <-done:
+				t.Fatal("task executed while paused")
+			default:
+			}
+
+			pool.Resume()
+			<-done
+			<-done
+		})
+	}
+}
+
+func TestPoolsReleaseContext(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			// 等待已接受的任务执行完；释放开始（池子已关闭）后才放行，任务结束计入报告
+			pool := tp.new(2)
+			gate := make(chan struct{})
+			for i := 0; i < 2; i++ {
+				_ = pool.Submit(func() { <-gate })
+			}
+			go func() {
+				for !pool.Closed() {
+					runtime.Gosched()
+				}
+				close(gate)
+			}()
+			report, err := pool.ReleaseContext(context.Background(), false)
+			if err != nil || report.Completed != 2 || report.Running != 0 || len(report.Dropped) != 0 {
+				t.Fatalf("drain: report = %+v, err = %v", report, err)
+			}
+
+			// 丢弃尚未开始执行的任务并返回给调用方
+			pool = tp.new(2)
+			pool.Pause()
+			var executed atomic.Int32
+			for i := 0; i < 2; i++ {
+				_ = pool.Submit(func() { executed.Add(1) })
+			}
+			report, err = pool.ReleaseContext(context.Background(), true)
+			if err != nil || report.Completed != 0 || len(report.Dropped) != 2 {
+				t.Fatalf("drop: report = %+v, err = %v", report, err)
+			}
+			if dropped := pool.Stats().Dropped; dropped != 2 {
+				t.Fatalf("stats dropped = %d, want 2", dropped)
+			}
+			for _, task := range report.Dropped {
+				task()
+			}
+			if n := executed.Load(); n != 2 {
+				t.Fatalf("executed = %d, want 2 after running dropped tasks", n)
+			}
+		})
+	}
+}
+
+func TestPoolsReleaseRejectsBlocked(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			blocked := make(chan struct{}, 1)
+			pool := tp.new(1, WithHooks(Hooks{OnBlock: func() { blocked <- struct{}{} }}))
+			gate := make(chan struct{})
+			_ = pool.Submit(func() { <-gate })
+			errs := make(chan error, 1)
+			go func() {
+				errs <- pool.Submit(func() {})
+			}()
+			<-blocked
+
+			// 释放时阻塞的提交方失败返回，不会超出容量新建worker
+			pool.Release()
+			if err := <-errs; !errors.Is(err, errors.ErrorSchedulerClosed) {
+				t.Fatalf("blocked submit err = %v, want scheduler closed", err)
+			}
+			if running := pool.Running(); running != 1 {
+				t.Fatalf("running = %d, want 1", running)
+			}
+			close(gate)
+			pool.Wait()
+		})
+	}
+}
+
+// ReleaseContext 的ctx结束时，报告仍在执行的任务；执行阶段的ctx随池子的运行上下文取消，而不是停留在提交方ctx
+func TestPoolsReleaseContextCancelsTasks(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			recorder := tracingtest.NewRecorder()
+			pool := tp.new(1, WithTracer(recorder))
+
+			started := make(chan struct{})
+			_ = pool.SubmitContext(context.Background(), func() {
+				close(started)
+				<-pool.Context().Done()
+			})
+			<-started
+
+			releaseCtx, cancel := context.WithCancel(context.Background())
+			cancel()
+			report, err := pool.ReleaseContext(releaseCtx, false)
+			if !errors.Is(err, errors.ErrorPoolReleaseTimeout) || report.Running != 1 {
+				t.Fatalf("release: report = %+v, err = %v", report, err)
+			}
+			pool.Wait()
+
+			spans := recorder.Ended(tracing.SpanExecute)
+			if len(spans) != 1 || len(spans[0].Errors) != 1 || !errors.Is(spans[0].Errors[0], context.Canceled) {
+				t.Fatalf("execute spans = %+v", spans)
+			}
+		})
+	}
+}
+
+func TestPoolsDrain(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(3)
+			pool.Pause()
+			var executed atomic.Int32
+			for i := 0; i < 3; i++ {
+				_ = pool.Submit(func() { executed.Add(1) })
+			}
+			pending := pool.Drain()
+			if len(pending) != 3 || executed.Load() != 0 {
+				t.Fatalf("pending = %d, executed = %d, want 3, 0", len(pending), executed.Load())
+			}
+			if !pool.Closed() || pool.Running() != 0 {
+				t.Fatalf("closed = %v, running = %d after drain", pool.Closed(), pool.Running())
+			}
+
+			// 收集的任务可以转交给其他池子
+			other := tp.new(3)
+			defer other.Release()
+			var wg sync.WaitGroup
+			wg.Add(len(pending))
+			for _, task := range pending {
+				_ = other.Submit(func() {
+					task()
+					wg.Done()
+				})
+			}
+			wg.Wait()
+			if n := executed.Load(); n != 3 {
+				t.Fatalf("executed = %d, want 3", n)
+			}
+		})
+	}
+}
+
+// 超时时返回已收集到的任务：执行中的任务只在运行上下文取消后退出，释放必然超时
+func TestPoolsReleaseAndCollectTimeout(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(2)
+			started := make(chan struct{})
+			_ = pool.Submit(func() {
+				close(started)
+				<-pool.Context().Done()
+			})
+			<-started
+			pool.Pause()
+			var executed atomic.Int32
+			_ = pool.Submit(func() { executed.Add(1) })
+
+			pending, err := pool.ReleaseAndCollect(20 * time.Millisecond)
+			if !errors.Is(err, errors.ErrorPoolReleaseTimeout) || len(pending) != 1 {
+				t.Fatalf("pending = %d, err = %v", len(pending), err)
+			}
+			pool.Wait()
+			if n := executed.Load(); n != 0 {
+				t.Fatalf("executed = %d, want the collected task left unstarted", n)
+			}
+		})
+	}
+}
+
+// 释放超时时，暂停期间持有的任务同样出现在报告里，而不是在读取之后才被丢弃
+func TestPoolsReleaseContextDropsHeldOnTimeout(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(2)
+			pool.Pause()
+			var executed atomic.Int32
+			for i := 0; i < 2; i++ {
+				_ = pool.Submit(func() { executed.Add(1) })
+			}
+
+			releaseCtx, cancel := context.WithCancel(context.Background())
+			cancel()
+			report, _ := pool.ReleaseContext(releaseCtx, true)
+			pool.Wait()
+			if len(report.Dropped) != 2 || executed.Load() != 0 {
+				t.Fatalf("dropped = %d, executed = %d, want 2, 0", len(report.Dropped), executed.Load())
+			}
+			if dropped := pool.Stats().Dropped; dropped != 2 {
+				t.Fatalf("stats dropped = %d, want 2", dropped)
+			}
+		})
+	}
+}
+
+// 未暂停时与提交交错地 Drain，被接受的任务要么执行完，要么出现在返回结果里
+func TestPoolsDrainWithoutPause(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			pool := tp.new(4)
+			var accepted, executed atomic.Int64
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					for {
+						if pool.Submit(func() { executed.Add(1) }) != nil {
+							return
+						}
+						accepted.Add(1)
+					}
+				}()
+			}
+			close(start)
+			for accepted.Load() < 100 {
+				runtime.Gosched()
+			}
+
+			dropped := pool.Drain()
+			wg.Wait()
+			if got, want := executed.Load()+int64(len(dropped)), accepted.Load(); got != want {
+				t.Fatalf("executed %d + dropped %d != accepted %d", executed.Load(), len(dropped), want)
+			}
+			if s := pool.Stats(); s.Running != 0 {
+				t.Fatalf("running = %d after drain", s.Running)
+			}
+		})
+	}
+}
+
+func TestPoolsStats(t *testing.T) {
+	for _, tp := range testPools {
+		t.Run(tp.name, func(t *testing.T) {
+			// 完成计数先于 OnTaskEnd，panic计数先于处理器，两者都返回后统计才完整
+			ended := make(chan struct{}, 2)
+			panicked := make(chan struct{}, 1)
+			pool := tp.new(
+				2,
+				WithNonblocking(true),
+				WithPanicHandler(func(any) { panicked <- struct{}{} }),
+				WithHooks(Hooks{OnTaskEnd: func(time.Duration, bool) { ended <- struct{}{} }}),
+				WithExpiryDuration(10*time.Second),
+			)
+			defer pool.Release()
+
+			gate := make(chan struct{})
+			_ = pool.Submit(func() { <-gate })
+			_ = pool.Submit(func() {
+				<-gate
+				panic("boom")
+			})
+			if stats := pool.Stats(); stats.Busy != 2 || stats.Idle != 0 {
+				t.Fatalf("busy = %d, idle = %d, want 2, 0", stats.Busy, stats.Idle)
+			}
+			if err := pool.Submit(func() {}); err == nil {
+				t.Fatal("submit to full nonblocking pool should fail")
+			}
+			close(gate)
+			<-ended
+			<-ended
+			<-panicked
+
+			stats := pool.Stats()
+			if stats.Submitted != 2 || stats.Completed != 1 || stats.Rejected != 1 || stats.Failed != 0 || stats.Panicked != 1 {
+				t.Fatalf("submitted = %d, completed = %d, rejected = %d, failed = %d, panicked = %d",
+					stats.Submitted, stats.Completed, stats.Rejected, stats.Failed, stats.Panicked)
+			}
+			if stats.PeakRunning != 2 || stats.WorkersCreated < 2 {
+				t.Fatalf("peak running = %d, workers created = %d", stats.PeakRunning, stats.WorkersCreated)
+			}
+		})
+	}
+}
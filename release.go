@@ -0,0 +1,44 @@
+package turbopool
+
+import (
+	"context"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// ReleaseContext 的释放报告
+type ReleaseReport[T any] struct {
+	Completed int64 // 释放期间执行结束（含panic）的任务数
+	Dropped   []T   // 已分配到worker但未开始执行而被丢弃的任务，仅在drop时有
+	Running   int32 // ctx结束时仍在执行的任务数，完全释放时为0
+}
+
+// 释放期间执行结束的任务数
+func completedSince(before, after stats.Stats) int64 {
+	return after.Completed + after.Panicked - before.Completed - before.Panicked
+}
+
+// 任务执行阶段的上下文：值（追踪、pprof标签等）来自提交方ctx，取消信号来自池子的运行上下文，
+// ReleaseContext 的ctx结束时执行阶段的span能看到取消；只在开启追踪时于执行阶段生成，默认的提交路径不分配
+type taskContext struct {
+	context.Context                 // 提交方ctx，提交成功后它的取消不再影响任务
+	run             context.Context // 池子的运行上下文
+}
+
+func (c taskContext) Deadline() (time.Time, bool) {
+	return c.run.Deadline()
+}
+
+func (c taskContext) Done() <-chan struct{} {
+	return c.run.Done()
+}
+
+func (c taskContext) Err() error {
+	return c.run.Err()
+}
+
+// 由提交方ctx和池子的运行上下文生成任务执行阶段的上下文
+func newTaskContext(taskCtx, runCtx context.Context) context.Context {
+	return taskContext{Context: taskCtx, run: runCtx}
+}
@@ -0,0 +1,42 @@
+package turbopool
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// runtime/trace 中的任务、区域与日志类别名称
+const (
+	TraceTask          = "turbopool.task"    // 每次提交对应的 trace.Task
+	TraceRegionWait    = "turbopool.wait"    // 等待worker的区域
+	TraceRegionExecute = "turbopool.execute" // 执行任务的区域
+	TraceCategory      = "turbopool.worker"  // worker生命周期日志的类别
+)
+
+// ctx中保存提交时创建的 trace.Task，由执行结束的worker结束它
+type traceTaskKey struct{}
+
+// 为本次提交创建 trace.Task，未开启或未在采集trace时返回nil
+func (opts *Options) startTraceTask(c context.Context) (context.Context, *trace.Task) {
+	if !opts.RuntimeTrace || !trace.IsEnabled() {
+		return c, nil
+	}
+	c, task := trace.NewTask(c, TraceTask)
+	if opts.Name != "" {
+		trace.Log(c, "pool", opts.Name)
+	}
+	return context.WithValue(c, traceTaskKey{}, task), task
+}
+
+// 获取提交时创建的 trace.Task
+func traceTaskFromContext(c context.Context) *trace.Task {
+	task, _ := c.Value(traceTaskKey{}).(*trace.Task)
+	return task
+}
+
+// 记录worker生命周期事件
+func (opts *Options) traceWorker(event string) {
+	if opts.RuntimeTrace && trace.IsEnabled() {
+		trace.Log(context.Background(), TraceCategory, event)
+	}
+}
@@ -1,13 +1,22 @@
 package turbopool
 
 import (
+	"context"
+	"log/slog"
 	"runtime/debug"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/gaohao-creator/turbopool/breaker"
 	"github.com/gaohao-creator/turbopool/errors"
+	"github.com/gaohao-creator/turbopool/hooks"
 	"github.com/gaohao-creator/turbopool/scheduler_generic"
+	"github.com/gaohao-creator/turbopool/stats"
+	"github.com/gaohao-creator/turbopool/tracing"
 )
 
 type scheduler[T any] struct {
@@ -25,6 +34,54 @@ type scheduler[T any] struct {
 	running      atomic.Int32                 // 正在运行的worker数量
 	waiting      atomic.Int32                 // 等待的任务数
 
+	// 任务统计
+	submitted atomic.Int64 // 成功分配到worker的任务数
+	completed atomic.Int64 // 正常执行完成的任务数
+	failed    atomic.Int64 // 提交失败的任务数
+	panicked  atomic.Int64 // 执行中panic的任务数
+	rejected  atomic.Int64 // 调度器已满、熔断器打开或池子已关闭被拒绝的任务数
+	timedOut  atomic.Int64 // 等待worker超时的任务数
+
+	droppedTotal atomic.Int64 // 释放时被丢弃的任务数
+
+	// worker统计
+	created            atomic.Int64 // 启动过的worker数量
+	expired            atomic.Int64 // 空闲过期被清理的worker数量
+	recycledByTasks    atomic.Int64 // 因任务数达到上限回收的worker数量
+	recycledByLifetime atomic.Int64 // 因存活时长达到上限回收的worker数量
+	peakRunning        atomic.Int32 // 运行worker数量峰值
+	peakWaiting        atomic.Int32 // 等待任务数量峰值
+
+	// 耗时直方图，未开启时为nil
+	waitLatency *stats.Histogram // 任务等待worker的耗时
+	execLatency *stats.Histogram // 任务执行耗时
+
+	// 熔断器，未开启时为nil
+	breaker *breaker.Breaker
+
+	// 释放时丢弃尚未开始执行的任务
+	dropping atomic.Bool
+	dropped  []T          // 由lock保护
+	pending  atomic.Int64 // 已分配到worker但尚未开始执行或被丢弃的任务数
+
+	// 池子的运行上下文，任务执行阶段的取消信号来源
+	runCtx atomic.Pointer[context.Context]
+
+	// 暂停状态，暂停期间worker在执行任务前等待resume关闭
+	paused    atomic.Bool
+	pauseLock sync.Mutex
+	resume    chan struct{}
+
+	// 任务执行期间的pprof标签，未开启时为nil
+	labels *pprof.LabelSet
+
+	// 正在执行的任务，开启任务登记或慢任务巡检时才记录，否则为nil
+	runningTasks *stats.RunningTasks
+
+	// 阻塞等待的提交方，由lock保护
+	waiterID uint64
+	waiters  map[uint64]time.Time
+
 	// 任务运行层次控制
 	preHook  func()  // 前置钩子
 	postHook func()  // 后置钩子
@@ -39,8 +96,49 @@ type scheduler[T any] struct {
 	//Logger           logger.LoggerV1  // 自定义日志处理器
 }
 
-// 获取worker
-func (s *scheduler[T]) Get() (scheduler_generic.Worker[T], error) {
+// 获取worker，并按结果记录提交统计
+func (s *scheduler[T]) Get(ctx context.Context) (scheduler_generic.Worker[T], error) {
+	var begin time.Time
+	if s.waitLatency != nil {
+		begin = s.Now()
+	}
+	w, err := s.get(ctx)
+	if err != nil {
+		s.Reject(err)
+		return nil, err
+	}
+	s.submitted.Add(1)
+	s.pending.Add(1)
+	if s.waitLatency != nil {
+		s.waitLatency.Record(s.Now().Sub(begin))
+	}
+	return w, nil
+}
+
+// 记录提交失败：按原因计数，触发 OnReject 并写入日志；池子已关闭等未经Get的拒绝也由池子交给这里
+func (s *scheduler[T]) Reject(err error) {
+	switch err {
+	case errors.ErrorSchedulerIsFull, errors.ErrorBreakerOpen, errors.ErrorPoolClosed:
+		s.rejected.Add(1)
+	case errors.ErrorSubmitTaskTimeout:
+		s.timedOut.Add(1)
+	default:
+		s.failed.Add(1)
+	}
+	if s.options.Hooks.OnReject != nil {
+		s.options.Hooks.OnReject(err)
+	}
+	if s.options.Slog != nil {
+		s.options.logAttrs(s.options.SlogLevels.Reject, "submit rejected",
+			slog.String("error", err.Error()), slog.Int("waiting", int(s.Waiting())), slog.Int("cap", int(s.Cap())))
+	}
+}
+
+func (s *scheduler[T]) get(ctx context.Context) (scheduler_generic.Worker[T], error) {
+	// 0) 熔断器打开时快速拒绝
+	if s.breaker != nil && !s.breaker.Allow(s.Now()) {
+		return nil, errors.ErrorBreakerOpen
+	}
 	// 1) 先尝试从 ready 队列获取
 	if w, err := s.readyWorkers.Pop(); err == nil {
 		return w, nil
@@ -48,7 +146,7 @@ func (s *scheduler[T]) Get() (scheduler_generic.Worker[T], error) {
 
 	// 2) ready 为空时再判断是否需要阻塞
 	if s.state.Load() == STATE_OPENED && s.Free() <= 0 {
-		if err := s.blocking(); err != nil {
+		if err := s.blocking(ctx); err != nil {
 			return nil, err
 		}
 		// 阻塞结束后再尝试 Pop 一次
@@ -57,10 +155,18 @@ func (s *scheduler[T]) Get() (scheduler_generic.Worker[T], error) {
 		}
 	}
 
-	// 3) 需要新建 worker
+	// 3) 需要新建 worker：与 tryDone 互斥地检查状态并计入运行数，
+	// 释放后不再新建，保证完成通知之后没有任务再被接受
+	s.lock.Lock()
+	if s.Closed() {
+		s.lock.Unlock()
+		return nil, errors.ErrorSchedulerClosed
+	}
+	s.addRunning(1)
+	s.lock.Unlock()
 	w := s.cacheWorkers.Get().(scheduler_generic.Worker[T])
 	w.Run()
-	s.addRunning(1)
+	s.created.Add(1)
 	return w, nil
 }
 
@@ -70,11 +176,17 @@ func (s *scheduler[T]) PutReady(w scheduler_generic.Worker[T]) error {
 	if s.state.Load() == STATE_CLOSED {
 		return errors.ErrorSchedulerClosed
 	}
+	// 先刷新时间再入栈，保证清理时读到的是入栈前写入的时间
+	w.Refresh()
 	if err := s.readyWorkers.Push(w); err != nil {
 		return err
 	}
-	w.Refresh()
-	s.cond.Signal()
+	if s.Closed() {
+		// 与 Release 交错时worker可能在清空之后才入栈，再清空一次让它退出
+		_ = s.readyWorkers.Clear()
+		return nil
+	}
+	s.wake(false)
 	return nil
 }
 
@@ -82,24 +194,152 @@ func (s *scheduler[T]) PutReady(w scheduler_generic.Worker[T]) error {
 func (s *scheduler[T]) PutCache(w scheduler_generic.Worker[T]) error {
 	s.addRunning(-1)
 	s.cacheWorkers.Put(w)
-	s.cond.Signal()
+	s.wake(false)
+	if s.Closed() {
+		s.tryDone() // 释放后最后一个worker退出时通知完成
+	}
 	return nil
 }
 
-// 统一处理任务 panic，优先使用自定义处理器或日志
-func (s *scheduler[T]) Recover() {
-	p := recover()
+// 处理任务之外（worker启动、退出钩子等）引起的 panic，p为recover()的返回值
+func (s *scheduler[T]) Recover(p any) {
 	if p == nil {
 		return
 	}
-	if ph := s.options.PanicHandler; ph != nil {
-		ph(p)
-		return
+	if r, ok := p.(*rePanic); ok {
+		panic(r.value) // 任务panic已处理，按 RePanic 崩溃
 	}
-	if logger := s.options.Logger; logger != nil {
-		logger.Printf("worker exits from panic: %v\n%s\n", p, debug.Stack())
+	s.panicked.Add(1)
+	s.options.handlePanic(PanicInfo{Value: p, Stack: debug.Stack(), PoolName: s.options.Name})
+}
+
+// 处理任务 panic，p为nil表示任务调用了 runtime.Goexit
+func (s *scheduler[T]) recoverTask(p any, task T, startedAt time.Time) {
+	if p == nil {
 		return
 	}
+	s.panicked.Add(1)
+	s.options.handlePanic(PanicInfo{Value: p, Stack: debug.Stack(), Task: task, PoolName: s.options.Name, StartedAt: startedAt})
+}
+
+// 记录未能执行的任务：计入失败数和熔断器失败率，并与panic一样交给处理器报告
+func (s *scheduler[T]) Fail(ctx context.Context, task T, err error) {
+	s.leavePending()
+	s.fail(ctx, task, err)
+}
+
+func (s *scheduler[T]) fail(ctx context.Context, task T, err error) {
+	if traceTask := traceTaskFromContext(ctx); traceTask != nil {
+		traceTask.End()
+	}
+	s.failed.Add(1)
+	if b := s.breaker; b != nil {
+		gen := b.Generation()
+		b.Failure(s.Now(), gen)
+		b.Done(s.Now(), gen)
+	}
+	s.options.handleTaskFailure(PanicInfo{Value: err, Task: task, PoolName: s.options.Name})
+}
+
+// 执行任务：创建执行span，记录完成数、执行耗时并触发任务钩子；panic时恢复并交给panic处理器，返回任务是否panic
+func (s *scheduler[T]) Execute(ctx context.Context, task T, handler func(T)) (panicked bool) {
+	return s.ExecuteWith(ctx, task, scheduler_generic.ExecInfo{}, handler)
+}
+
+// 与 Execute 相同，任务通过暂停与丢弃检查后、开始执行前调用 info.Prepare（如初始化worker状态），
+// 返回错误时任务不执行，按 Fail 计入失败数并交给panic处理器报告；登记正在执行的任务时使用 info.GoroutineID
+func (s *scheduler[T]) ExecuteWith(ctx context.Context, task T, info scheduler_generic.ExecInfo, handler func(T)) (panicked bool) {
+	if !s.admit(ctx, task) {
+		return false
+	}
+	if prepare := info.Prepare; prepare != nil {
+		if err := prepare(); err != nil {
+			s.fail(ctx, task, err)
+			return false
+		}
+	}
+	if h := s.options.Hooks.OnTaskStart; h != nil {
+		h()
+	}
+	begin := s.Now()
+	var generation uint64 // 熔断器状态代数，结果按任务开始时的代数上报
+	if s.breaker != nil {
+		generation = s.breaker.Generation()
+	}
+	timed := s.execLatency != nil || s.options.Hooks.OnTaskEnd != nil
+	run := s.runContext()
+	spanCtx := ctx
+	if !tracing.IsNoop(s.options.Tracer) {
+		spanCtx = newTaskContext(ctx, run) // 仅在开启追踪时合并，默认路径不分配
+	}
+	_, span := s.options.Tracer.Start(spanCtx, tracing.SpanExecute)
+	if traceTask := traceTaskFromContext(ctx); traceTask != nil {
+		defer traceTask.End()
+		defer trace.StartRegion(ctx, TraceRegionExecute).End()
+	}
+	if s.runningTasks != nil {
+		gid := info.GoroutineID
+		if gid == 0 {
+			gid = stats.CurrentGoroutineID() // worker未提供时才解析堆栈
+		}
+		id := s.runningTasks.Start(s.Now(), gid, task, ctx)
+		defer s.runningTasks.Finish(id)
+	}
+	panicked = true
+	defer func() {
+		if panicked {
+			span.RecordError(errors.ErrorTaskPanic) // panic 由 recoverTask 计数
+		} else {
+			s.completed.Add(1)
+		}
+		if err := run.Err(); err != nil {
+			span.RecordError(err) // 执行期间池子的运行上下文被取消（ReleaseContext 超时）
+		}
+		span.End()
+		if timed {
+			d := s.Now().Sub(begin)
+			if s.execLatency != nil {
+				s.execLatency.Record(d)
+			}
+			if h := s.options.Hooks.OnTaskEnd; h != nil {
+				h(d, panicked)
+			}
+		}
+		if s.breaker != nil {
+			if panicked {
+				s.breaker.Failure(s.Now(), generation)
+			}
+			s.breaker.Done(s.Now(), generation)
+		}
+		if panicked {
+			s.recoverTask(recover(), task, begin)
+		}
+	}()
+	if s.labels != nil {
+		// 标签合并自提交方ctx，执行结束（含panic）后恢复worker原有标签
+		pprof.Do(ctx, *s.labels, func(context.Context) {
+			handler(task)
+		})
+	} else {
+		handler(task)
+	}
+	return false // 返回值在defer执行前赋给panicked
+}
+
+// 记录worker goroutine启动
+func (s *scheduler[T]) WorkerStart() {
+	s.options.traceWorker("start")
+	if h := s.options.Hooks.OnWorkerStart; h != nil {
+		h()
+	}
+}
+
+// 记录worker goroutine退出
+func (s *scheduler[T]) WorkerExit(reason hooks.ExitReason) {
+	s.options.traceWorker("exit " + reason.String())
+	if h := s.options.Hooks.OnWorkerExit; h != nil {
+		h(reason)
+	}
 }
 
 func (s *scheduler[T]) Handler() func(T) {
@@ -113,25 +353,96 @@ func (s *scheduler[T]) ClearExpired(duration time.Duration) {
 	if duration == 0 && s.options.ExpiryDuration != 0 {
 		duration = s.options.ExpiryDuration
 	}
-	t := time.Now().Add(-duration)
-	clearCount, _ := s.readyWorkers.ClearExpired(t)
+	t := s.Now().Add(-duration)
+	var clearCount int
+	if kc, ok := s.readyWorkers.(scheduler_generic.KeepIdleClearer); ok {
+		clearCount, _ = kc.ClearExpiredKeep(t, s.options.MinIdleWorkers)
+	} else {
+		clearCount, _ = s.readyWorkers.ClearExpired(t) // 自定义容器不支持保留空闲worker
+	}
+	s.expired.Add(int64(clearCount))
+	if clearCount > 0 && s.options.Slog != nil {
+		s.options.logAttrs(s.options.SlogLevels.Expiry, "expired workers cleared",
+			slog.Int("cleared", clearCount), slog.Int("idle", s.readyWorkers.Len()), slog.Duration("expiry", duration))
+	}
 	// 清理后如有等待任务则唤醒
-	if clearCount > 0 && s.Waiting() > 0 {
-		s.cond.Broadcast() // 唤醒阻塞队列，因为free的空间增大了
+	if clearCount > 0 {
+		s.wake(true) // 唤醒阻塞队列，因为free的空间增大了
 	}
 }
 
+// 唤醒阻塞的提交方，all为true时唤醒全部。
+// 通知必须在持锁时发出，否则可能落在 blocking 检查条件与进入 Wait 之间而丢失；
+// waiting 在持锁检查条件前增加，读到0时提交方必然能看到本次释放的worker，可以跳过加锁
+func (s *scheduler[T]) wake(all bool) {
+	if s.Waiting() == 0 {
+		return
+	}
+	s.lock.Lock()
+	if all {
+		s.cond.Broadcast()
+	} else {
+		s.cond.Signal()
+	}
+	s.lock.Unlock()
+}
+
+// 预先启动n个worker放入就绪队列，受容量限制，返回实际启动的数量
+func (s *scheduler[T]) Prewarm(n int) int {
+	started := 0
+	for started < n && s.Opened() && s.Free() > 0 {
+		w := s.cacheWorkers.Get().(scheduler_generic.Worker[T])
+		w.Run()
+		s.created.Add(1)
+		s.addRunning(1)
+		if err := s.PutReady(w); err != nil {
+			w.Finish()
+			break
+		}
+		started++
+	}
+	return started
+}
+
+// 判断worker是否达到回收条件（任务数或存活时长），达到时记录回收原因
+func (s *scheduler[T]) Recycle(tasks int, createdTime time.Time) bool {
+	if maxTasks := s.options.WorkerMaxTasks; maxTasks > 0 && tasks >= maxTasks {
+		s.recycledByTasks.Add(1)
+		return true
+	}
+	if lifetime := s.options.WorkerMaxLifetime; lifetime > 0 && s.Now().Sub(createdTime) >= lifetime {
+		s.recycledByLifetime.Add(1)
+		return true
+	}
+	return false
+}
+
+// 当前时间（由配置的时钟提供）
+func (s *scheduler[T]) Now() time.Time {
+	return s.options.Clock.Now()
+}
+
 // Release 关闭调度器并清空就绪的worker，同时避免阻塞的goroutine泄露
 func (s *scheduler[T]) Release() {
 	s.Close()
+	s.Resume() // 暂停中已分配到worker的任务继续执行，避免worker永远阻塞
 
 	// 清空就绪 worker 释放内存
 	s.readyWorkers.Clear()
 
-	// 唤醒所有等待方,避免goroutine泄露
+	// 唤醒所有等待方,避免goroutine泄露；持锁通知，保证等待方要么已在 Wait，要么随后看到关闭状态
+	s.lock.Lock()
 	s.cond.Broadcast()
+	s.lock.Unlock()
 
-	// 最终检查并关闭 done
+	// 没有运行中的worker时直接通知完成，否则由最后退出的worker通知
+	s.tryDone()
+}
+
+// 调度器已关闭且所有worker退出后关闭 done
+func (s *scheduler[T]) tryDone() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	if s.Closed() && s.Running() == 0 {
 		s.doneOnce.Do(func() {
 			close(s.done) // 通知调度器已完成
@@ -139,6 +450,23 @@ func (s *scheduler[T]) Release() {
 	}
 }
 
+// 重启已释放的调度器：重建完成信号并重新打开，未释放时不做任何事。
+// 超时释放后仍有任务在执行时旧的完成信号尚未关闭，先关闭它，避免仍在 Wait 的调用永远阻塞
+func (s *scheduler[T]) Reboot() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.Closed() {
+		return
+	}
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+	s.done = make(chan struct{})
+	s.doneOnce = &sync.Once{}
+	s.dropping.Store(false)
+	s.state.Store(STATE_OPENED)
+}
+
 func (s *scheduler[T]) Wait() {
 	if s.Running() > 0 {
 		<-s.Done()
@@ -171,6 +499,102 @@ func (s *scheduler[T]) Waiting() int32 {
 	return s.waiting.Load()
 }
 
+// 正在执行任务的worker数量（运行中减去就绪队列中空闲的）
+func (s *scheduler[T]) Working() int32 {
+	return max(s.running.Load()-int32(s.readyWorkers.Len()), 0)
+}
+
+func (s *scheduler[T]) RecycledByTasks() int64 {
+	return s.recycledByTasks.Load()
+}
+
+func (s *scheduler[T]) RecycledByLifetime() int64 {
+	return s.recycledByLifetime.Load()
+}
+
+// 运行状态快照，各项分别读取，彼此之间不保证严格一致
+func (s *scheduler[T]) Stats() stats.Stats {
+	running := s.running.Load()
+	idle := min(int32(s.readyWorkers.Len()), running)
+	byTasks, byLifetime := s.recycledByTasks.Load(), s.recycledByLifetime.Load()
+	breakerState, breakerOpens := breaker.StateClosed, int64(0)
+	if s.breaker != nil {
+		breakerState, breakerOpens = s.breaker.State(), s.breaker.Opens()
+	}
+	return stats.Stats{
+		Cap:                s.capacity.Load(),
+		Running:            running,
+		Waiting:            s.waiting.Load(),
+		Busy:               running - idle,
+		Idle:               idle,
+		Submitted:          s.submitted.Load(),
+		Completed:          s.completed.Load(),
+		Failed:             s.failed.Load(),
+		Panicked:           s.panicked.Load(),
+		Rejected:           s.rejected.Load(),
+		TimedOut:           s.timedOut.Load(),
+		Dropped:            s.droppedTotal.Load(),
+		WorkersCreated:     s.created.Load(),
+		WorkersExpired:     s.expired.Load(),
+		WorkersRecycled:    byTasks + byLifetime,
+		RecycledByTasks:    byTasks,
+		RecycledByLifetime: byLifetime,
+		PeakRunning:        s.peakRunning.Load(),
+		PeakWaiting:        s.peakWaiting.Load(),
+		BreakerState:       breakerState,
+		BreakerOpens:       breakerOpens,
+		WaitLatency:        snapshotHistogram(s.waitLatency),
+		ExecLatency:        snapshotHistogram(s.execLatency),
+	}
+}
+
+// 正在执行的任务，未开启任务登记时返回nil
+// 是否登记正在执行的任务
+func (s *scheduler[T]) TracksTasks() bool {
+	return s.runningTasks != nil
+}
+
+func (s *scheduler[T]) RunningTasks() []stats.RunningTask {
+	if s.runningTasks == nil {
+		return nil
+	}
+	return s.runningTasks.List()
+}
+
+// 就绪worker及其空闲时长
+func (s *scheduler[T]) ReadyWorkers() []stats.ReadyWorker {
+	reader, ok := s.readyWorkers.(scheduler_generic.UsedTimesReader)
+	if !ok {
+		return nil
+	}
+	now := s.Now()
+	times := reader.UsedTimes()
+	workers := make([]stats.ReadyWorker, len(times))
+	for i, t := range times {
+		workers[i] = stats.ReadyWorker{LastUsed: t, Idle: now.Sub(t)}
+	}
+	return workers
+}
+
+// 熔断器，未开启时为nil
+func (s *scheduler[T]) Breaker() *breaker.Breaker {
+	return s.breaker
+}
+
+// 阻塞等待的提交方，按开始等待的顺序排列
+func (s *scheduler[T]) Waiters() []stats.Waiter {
+	s.lock.Lock()
+	waiters := make([]stats.Waiter, 0, len(s.waiters))
+	for id, since := range s.waiters {
+		waiters = append(waiters, stats.Waiter{ID: id, Since: since})
+	}
+	s.lock.Unlock()
+	sort.Slice(waiters, func(i, j int) bool {
+		return waiters[i].ID < waiters[j].ID
+	})
+	return waiters
+}
+
 func (s *scheduler[T]) Open() {
 	s.state.Store(STATE_OPENED)
 }
@@ -189,40 +613,202 @@ func (s *scheduler[T]) Closed() bool {
 	return s.state.Load() == STATE_CLOSED
 }
 
+// 释放时丢弃已分配到worker但尚未开始执行的任务，通过 Dropped 取回
+func (s *scheduler[T]) DropPending() {
+	s.dropping.Store(true)
+}
+
+// 取回被丢弃的任务
+func (s *scheduler[T]) Dropped() []T {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	dropped := s.dropped
+	s.dropped = nil
+	return dropped
+}
+
+// 设置池子的运行上下文，创建池子和重启时调用
+func (s *scheduler[T]) SetRunContext(ctx context.Context) {
+	s.runCtx.Store(&ctx)
+}
+
+// 池子的运行上下文，未设置时为不会取消的 context.Background
+func (s *scheduler[T]) runContext() context.Context {
+	if ctx := s.runCtx.Load(); ctx != nil {
+		return *ctx
+	}
+	return context.Background()
+}
+
+// 任务开始执行前处理暂停与丢弃，返回false表示任务已被丢弃
+func (s *scheduler[T]) admit(ctx context.Context, task T) bool {
+	defer s.leavePending()
+	if s.paused.Load() {
+		s.waitResume()
+	}
+	if s.dropping.Load() {
+		s.drop(ctx, task)
+		return false
+	}
+	return true
+}
+
+// 任务离开分配阶段（开始执行或被丢弃），丢弃期间最后一个离开时唤醒 WaitPending
+func (s *scheduler[T]) leavePending() {
+	if s.pending.Add(-1) == 0 && s.dropping.Load() {
+		s.lock.Lock()
+		s.cond.Broadcast()
+		s.lock.Unlock()
+	}
+}
+
+// 等待已分配到worker的任务都开始执行或被丢弃，在 DropPending 之后调用；
+// 这一阶段worker不执行任务代码，释放超时时也能很快等到，保证 Dropped 取回全部被丢弃的任务
+func (s *scheduler[T]) WaitPending() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for s.pending.Load() > 0 {
+		s.cond.Wait()
+	}
+}
+
+func (s *scheduler[T]) drop(ctx context.Context, task T) {
+	if traceTask := traceTaskFromContext(ctx); traceTask != nil {
+		traceTask.End()
+	}
+	s.droppedTotal.Add(1)
+	s.lock.Lock()
+	s.dropped = append(s.dropped, task)
+	s.lock.Unlock()
+}
+
+// 暂停执行新任务：已分配到worker的任务在开始执行前等待恢复，worker用完后提交阻塞或按非阻塞配置被拒绝
+func (s *scheduler[T]) Pause() {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+	if s.resume == nil {
+		s.resume = make(chan struct{})
+		s.paused.Store(true)
+	}
+}
+
+// 恢复执行，唤醒所有等待中的worker
+func (s *scheduler[T]) Resume() {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+	if s.resume != nil {
+		s.paused.Store(false)
+		close(s.resume)
+		s.resume = nil
+	}
+}
+
+// 是否已暂停
+func (s *scheduler[T]) Paused() bool {
+	return s.paused.Load()
+}
+
+// 等待恢复执行
+func (s *scheduler[T]) waitResume() {
+	s.pauseLock.Lock()
+	resume := s.resume
+	s.pauseLock.Unlock()
+	if resume != nil {
+		<-resume
+	}
+}
+
 func (s *scheduler[T]) Done() chan struct{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	return s.done
 }
 
 func (s *scheduler[T]) Scale(cap int32) {
-	s.capacity.Store(cap)
+	old := s.capacity.Swap(cap)
+	if old == cap {
+		return
+	}
+	_ = s.readyWorkers.Scale(cap)
+	// 扩容后唤醒阻塞的提交方
+	s.lock.Lock()
+	s.cond.Broadcast()
+	s.lock.Unlock()
+	if s.options.Slog != nil {
+		s.options.logAttrs(s.options.SlogLevels.Resize, "pool resized",
+			slog.Int("from", int(old)), slog.Int("to", int(cap)))
+	}
 }
 
 func (s *scheduler[T]) addRunning(delta int32) int32 {
-	return s.running.Add(delta)
+	running := s.running.Add(delta)
+	if delta > 0 {
+		stats.StoreMax(&s.peakRunning, running)
+	}
+	return running
 }
 
 // blocking 阻塞获取worker
-func (s *scheduler[T]) blocking() error {
+func (s *scheduler[T]) blocking(ctx context.Context) error {
+	// 阻塞钩子在锁外调用，钩子中可以读取调度器状态；OnUnblock 在解锁后执行
+	blocked := false
+	defer func() {
+		if blocked && s.options.Hooks.OnUnblock != nil {
+			s.options.Hooks.OnUnblock()
+		}
+	}()
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	// 检查调度器是否开启
 	if s.state.Load() == STATE_CLOSED {
 		return errors.ErrorSchedulerClosed
 	}
-	s.waiting.Add(1)
+	stats.StoreMax(&s.peakWaiting, s.waiting.Add(1))
+	s.waiterID++
+	id := s.waiterID
+	s.waiters[id] = s.Now()
+	// 注销等待登记，先于解锁执行
+	defer delete(s.waiters, id)
 	opened := s.Opened() //检查调度器是否处于开启状态
 	free := s.Free()     // 获取空闲worker数量（容量 - 运行数）
+	// ctx 可结束时，在结束时唤醒等待方；回调持有锁，避免在检查与等待之间丢失唤醒
+	if ctx.Done() != nil {
+		stop := context.AfterFunc(ctx, func() {
+			s.lock.Lock()
+			s.cond.Broadcast()
+			s.lock.Unlock()
+		})
+		defer stop()
+	}
 	for opened && free <= 0 && s.readyWorkers.IsEmpty() {
 		if s.options.Nonblocking ||
 			(s.options.MaxBlockingTasks != 0 && s.Waiting() >= int32(s.options.MaxBlockingTasks)) {
 			s.waiting.Add(-1)
 			return errors.ErrorSchedulerIsFull // 返回"调度器已满"错误
 		}
+		if ctx.Err() != nil {
+			s.waiting.Add(-1)
+			return errors.ErrorSubmitTaskTimeout // 等待期间ctx结束
+		}
+		if !blocked {
+			blocked = true
+			if h := s.options.Hooks.OnBlock; h != nil {
+				s.lock.Unlock()
+				h()
+				s.lock.Lock()
+				// 调用钩子期间可能错过唤醒，重新检查后再等待
+				opened, free = s.Opened(), s.Free()
+				continue
+			}
+		}
 		s.cond.Wait()       // 开始阻塞等待
 		opened = s.Opened() // 等待任务数 -1（获取到worker了，准备退出）
 		free = s.Free()
 	}
 	s.waiting.Add(-1)
+	if !opened {
+		return errors.ErrorSchedulerClosed // 等待期间调度器关闭，不再新建worker
+	}
 	return nil
 }
 
@@ -241,10 +827,28 @@ func NewSchedulerGeneric[T any](
 		cacheWorkers: &sync.Pool{},
 		running:      atomic.Int32{},
 		waiting:      atomic.Int32{},
+		waiters:      make(map[uint64]time.Time),
 		handler:      handler,
 		options:      opts,
 	}
 	s.cond = sync.NewCond(s.lock)
+	if opts.TrackTasks || opts.WatchdogThreshold > 0 {
+		s.runningTasks = stats.NewRunningTasks()
+	}
+	if opts.Breaker != nil {
+		s.breaker = breaker.New(*opts.Breaker, opts.Hooks.OnBreakerChange)
+	}
+	if opts.PprofLabels {
+		labels := pprof.Labels()
+		if opts.Name != "" {
+			labels = pprof.Labels(PprofLabelPool, opts.Name)
+		}
+		s.labels = &labels
+	}
+	if opts.Histograms {
+		s.waitLatency = &stats.Histogram{}
+		s.execLatency = &stats.Histogram{}
+	}
 	s.capacity.Store(cap)
 	s.cacheWorkers.New = func() any {
 		return workerFunc(s) // 如果不存在缓存，就调用工厂函数来生产一个新的对象
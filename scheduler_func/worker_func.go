@@ -1,32 +1,74 @@
 package scheduler_func
 
 import (
+	"context"
+	"runtime"
 	"time"
+
+	"github.com/gaohao-creator/turbopool/hooks"
+	"github.com/gaohao-creator/turbopool/stats"
 )
 
+// 任务及其提交时的上下文，fn为nil表示结束信号
+type taskWithFunc struct {
+	ctx context.Context
+	fn  func()
+}
+
 type workerWithFunc struct {
-	task      chan func() // 需要执行的task
-	scheduler Scheduler   // 这个worker受哪个scheduler控制
-	usedTime  time.Time   // 上次运行的时间
+	task        chan taskWithFunc // 需要执行的task
+	scheduler   Scheduler         // 这个worker受哪个scheduler控制
+	usedTime    time.Time         // 上次运行的时间
+	createdTime time.Time         // 本次运行的启动时间
+	tasks       int               // 本次运行已执行的任务数
+	lockThread  bool              // 是否绑定系统线程
 }
 
 func (w *workerWithFunc) Put(task func()) {
-	w.task <- task
+	w.PutContext(context.Background(), task)
+}
+
+func (w *workerWithFunc) PutContext(ctx context.Context, task func()) {
+	w.task <- taskWithFunc{ctx: ctx, fn: task}
 }
 
 func (w *workerWithFunc) Run() {
+	// worker 会从缓冲池复用，每次启动时重置回收计数
+	w.createdTime = w.scheduler.Now()
+	w.tasks = 0
 	go func() {
+		reason := hooks.ExitFinished // 退出原因
 		defer func() {
+			p := recover()
+			if p != nil {
+				reason = hooks.ExitPanicked
+			}
+			w.scheduler.WorkerExit(reason)
 			_ = w.scheduler.PutCache(w) // 将对象放在缓冲池中
-			w.scheduler.Recover()       // 有报错就处理报错
+			w.scheduler.Recover(p)      // 有报错就处理报错
 		}()
+		if w.lockThread {
+			// 不解除绑定，goroutine退出时线程随之销毁，线程局部状态不会被其他goroutine复用
+			runtime.LockOSThread()
+		}
+		w.scheduler.WorkerStart()
+		var info ExecInfo
+		if w.scheduler.TracksTasks() {
+			info.GoroutineID = stats.CurrentGoroutineID() // 每个goroutine只获取一次
+		}
 		for task := range w.task {
-			if task == nil {
+			if task.fn == nil {
 				return
 			}
-			handler := w.scheduler.Handler() // 获取该scheduler的handler处理函数
-			handler(task)                    // 执行task
+			handler := w.scheduler.Handler()                          // 获取该scheduler的handler处理函数
+			w.scheduler.ExecuteWith(task.ctx, task.fn, info, handler) // 执行task，panic在其中恢复并处理，worker继续运行
+			w.tasks++
+			if w.scheduler.Recycle(w.tasks, w.createdTime) {
+				reason = hooks.ExitRecycled
+				return // 达到回收条件，主动退出
+			}
 			if err := w.scheduler.PutReady(w); err != nil {
+				reason = hooks.ExitClosed
 				return
 			}
 		}
@@ -34,11 +76,11 @@ func (w *workerWithFunc) Run() {
 }
 
 func (w *workerWithFunc) Finish() {
-	w.task <- nil
+	w.task <- taskWithFunc{}
 }
 
 func (w *workerWithFunc) Refresh() {
-	w.usedTime = time.Now()
+	w.usedTime = w.scheduler.Now()
 }
 
 func (w *workerWithFunc) GetUsedTime() time.Time {
@@ -47,8 +89,8 @@ func (w *workerWithFunc) GetUsedTime() time.Time {
 
 func NewWorkerWithFunc(s Scheduler) WorkerWithFunc {
 	return &workerWithFunc{
-		task:      make(chan func(), 1),
+		task:      make(chan taskWithFunc, 1),
 		scheduler: s,
-		usedTime:  time.Now(),
+		usedTime:  s.Now(),
 	}
 }
@@ -0,0 +1,25 @@
+package scheduler_func
+
+// 创建绑定系统线程的worker。
+// worker的goroutine在整个生命周期内独占一个系统线程，每次从就绪队列复用该worker时任务都在同一线程上执行；
+// goroutine退出（Finish、过期清理、回收）时不解除绑定，线程随之销毁。
+func NewWorkerWithFuncLockOSThread(s Scheduler) WorkerWithFunc {
+	return LockOSThread(NewWorkerWithFunc)(s)
+}
+
+// 将worker工厂包装为绑定系统线程的版本，只对实现了 ThreadLocker 的worker生效，其他worker原样返回；
+// 池子开启 WithLockOSThread 时会先检查工厂创建的worker，不支持时返回 ErrorLockOSThreadUnsupported
+func LockOSThread(factory func(Scheduler) WorkerWithFunc) func(Scheduler) WorkerWithFunc {
+	return func(s Scheduler) WorkerWithFunc {
+		w := factory(s)
+		if lw, ok := w.(ThreadLocker); ok {
+			lw.LockOSThread()
+		}
+		return w
+	}
+}
+
+// 绑定系统线程，在 Run 启动的goroutine中生效
+func (w *workerWithFunc) LockOSThread() {
+	w.lockThread = true
+}
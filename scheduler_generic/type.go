@@ -1,6 +1,19 @@
 package scheduler_generic
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/breaker"
+	"github.com/gaohao-creator/turbopool/hooks"
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// worker执行任务时提供给调度器的信息，见 Scheduler.ExecuteWith
+type ExecInfo struct {
+	GoroutineID uint64       // worker goroutine的ID，调度器登记正在执行的任务时使用；为0时调度器在执行时自行获取
+	Prepare     func() error // 通过暂停与丢弃检查后、开始执行前调用（如初始化worker状态），返回错误时任务不执行而是按 Fail 处理
+}
 
 type Worker[T any] interface {
 	Put(task T) // 添加任务
@@ -10,6 +23,37 @@ type Worker[T any] interface {
 	Refresh() // 更新运行时间
 }
 
+// 可接收任务上下文的worker，池子优先用它把提交方ctx（追踪、pprof标签等）传给执行阶段；
+// 只实现 Put 的worker执行任务时拿不到提交方ctx
+type ContextPutter[T any] interface {
+	PutContext(ctx context.Context, task T) // 添加任务，ctx随任务传递给执行阶段
+}
+
+// 向worker添加任务，worker实现了 ContextPutter 时携带ctx
+func PutWithContext[T any](w Worker[T], ctx context.Context, task T) {
+	if cp, ok := w.(ContextPutter[T]); ok {
+		cp.PutContext(ctx, task)
+		return
+	}
+	w.Put(task)
+}
+
+// 可绑定系统线程的worker，自定义worker实现它才能配合 WithLockOSThread 使用
+type ThreadLocker interface {
+	LockOSThread() // 之后启动的goroutine在整个生命周期内绑定系统线程
+}
+
+// 清理时可以保留最少空闲worker的容器，开启 WithMinIdleWorkers 时调度器优先使用；
+// 未实现时过期清理不保留空闲worker
+type KeepIdleClearer interface {
+	ClearExpiredKeep(t time.Time, keep int) (int, error) // 清理过期worker，至少保留keep个，返回清理数量
+}
+
+// 可列出就绪worker上次运行时间的容器，调试快照据此展示就绪worker；未实现时快照中没有就绪worker明细
+type UsedTimesReader interface {
+	UsedTimes() []time.Time // 各worker的上次运行时间，按入栈顺序
+}
+
 type Workers[T any] interface {
 	Len() int
 	IsEmpty() bool
@@ -21,25 +65,53 @@ type Workers[T any] interface {
 }
 
 type Scheduler[T any] interface {
-	Get() (Worker[T], error)             // 获取worker
-	Handler() func(T)                    // 任务处理逻辑
-	PutReady(w Worker[T]) error          // 将worker放入就绪队列
-	PutCache(w Worker[T]) error          // 将worker放入sync.Pool
-	Recover()                            // 统一处理任务 panic，优先使用自定义处理器或日志
-	ClearExpired(duration time.Duration) // 清理过期worker
-
-	Cap() int32     // worker总容量
-	Free() int32    // 当前还可容纳的worker数量
-	Running() int32 // 当前正在运行的worker总数量
-	Waiting() int32 // 阻塞模式下等待的任务数量
+	Get(ctx context.Context) (Worker[T], error)                // 获取worker，阻塞等待时ctx结束则返回超时错误
+	Reject(err error)                                          // 记录提交失败：计数、触发 OnReject 并写入日志
+	Handler() func(T)                                          // 任务处理逻辑
+	PutReady(w Worker[T]) error                                // 将worker放入就绪队列
+	PutCache(w Worker[T]) error                                // 将worker放入sync.Pool
+	Recover(p any)                                             // 处理任务之外引起的 panic（p为recover()的返回值）
+	Execute(ctx context.Context, task T, handler func(T)) bool // 执行任务并记录统计、钩子与追踪，任务panic时恢复并处理，返回是否panic
+	// 与 Execute 相同，附带worker提供的执行信息
+	ExecuteWith(ctx context.Context, task T, info ExecInfo, handler func(T)) bool
+	TracksTasks() bool                             // 是否登记正在执行的任务，开启时worker在goroutine启动时获取一次 ExecInfo.GoroutineID
+	Fail(ctx context.Context, task T, err error)   // 记录未能执行的任务（如worker初始化失败），计入失败数并交给panic处理器报告
+	WorkerStart()                                  // 记录worker goroutine启动
+	WorkerExit(reason hooks.ExitReason)            // 记录worker goroutine退出
+	ClearExpired(duration time.Duration)           // 清理过期worker
+	Now() time.Time                                // 当前时间（由调度器的时钟提供）
+	Prewarm(n int) int                             // 预先启动n个worker，返回实际启动数量
+	Recycle(tasks int, createdTime time.Time) bool // 判断worker是否需要回收（按任务数或存活时长）
+
+	Cap() int32                        // worker总容量
+	Free() int32                       // 当前还可容纳的worker数量
+	Running() int32                    // 当前正在运行的worker总数量
+	Waiting() int32                    // 阻塞模式下等待的任务数量
+	Working() int32                    // 正在执行任务的worker数量
+	RecycledByTasks() int64            // 因任务数达到上限而回收的worker数量
+	RecycledByLifetime() int64         // 因存活时长达到上限而回收的worker数量
+	Stats() stats.Stats                // 运行状态快照
+	RunningTasks() []stats.RunningTask // 正在执行的任务（开启任务登记或慢任务巡检时记录）
+	ReadyWorkers() []stats.ReadyWorker // 就绪worker及其空闲时长
+	Waiters() []stats.Waiter           // 阻塞等待的提交方
+	Breaker() *breaker.Breaker         // 熔断器，未开启时为nil
 	Opened() bool
 	Closed() bool
+	Paused() bool
+
+	Open()        // 开始调度
+	Close()       // 结束调度
+	Pause()       // 暂停执行新任务，每个worker持有一个待执行任务，worker用完后提交阻塞或被拒绝
+	Resume()      // 恢复执行
+	Wait()        // 等待任务完成
+	Release()     // 释放资源
+	Reboot()      // 重启已释放的调度器
+	DropPending() // 释放时丢弃尚未开始执行的任务
+	WaitPending() // 等待已分配到worker的任务都开始执行或被丢弃
 
-	Open()               // 开始调度
-	Close()              // 结束调度
-	Wait()               // 等待任务完成
-	Release()            // 释放资源
-	Done() chan struct{} // 调度器生命周期的监听
+	SetRunContext(ctx context.Context) // 设置池子的运行上下文，执行阶段的span随它取消
+	Dropped() []T                      // 取回被丢弃的任务
+	Done() chan struct{}               // 调度器生命周期的监听
 
 	Scale(cap int32)
 }
@@ -1,35 +1,89 @@
 package scheduler_generic
 
 import (
+	"context"
+	"runtime"
 	"time"
+
+	"github.com/gaohao-creator/turbopool/hooks"
+	"github.com/gaohao-creator/turbopool/stats"
 )
 
+// 任务及其提交时的上下文
+type taskCtx[T any] struct {
+	ctx   context.Context
+	value T
+}
+
 type worker[T any] struct {
-	task      chan T        // 需要执行的task
-	exit      chan struct{} // 退出信号通知
-	scheduler Scheduler[T]  // 这个worker受哪个scheduler控制
-	usedTime  time.Time     // 上次运行的时间
+	task        chan taskCtx[T] // 需要执行的task
+	exit        chan struct{}   // 退出信号通知
+	scheduler   Scheduler[T]    // 这个worker受哪个scheduler控制
+	usedTime    time.Time       // 上次运行的时间
+	createdTime time.Time       // 本次运行的启动时间
+	tasks       int             // 本次运行已执行的任务数
+	lockThread  bool            // 是否绑定系统线程
+	info        ExecInfo        // 执行任务时提供给scheduler的信息
+
+	// 可选的生命周期钩子，由带状态的worker等变体设置
+	start   func()       // goroutine启动时执行
+	stop    func()       // goroutine退出时执行
+	handle  func(T)      // 任务处理函数，为空时使用scheduler的handler
+	prepare func() error // 执行任务前的检查，返回错误时任务不执行，按scheduler的Fail处理
 }
 
 func (w *worker[T]) Put(task T) {
-	w.task <- task
+	w.PutContext(context.Background(), task)
+}
+
+func (w *worker[T]) PutContext(ctx context.Context, task T) {
+	w.task <- taskCtx[T]{ctx: ctx, value: task}
 }
 
 func (w *worker[T]) Run() {
+	// worker 会从缓冲池复用，每次启动时重置回收计数
+	w.createdTime = w.scheduler.Now()
+	w.tasks = 0
 	go func() {
+		reason := hooks.ExitFinished // 退出原因
 		defer func() {
+			p := recover()
+			if p != nil {
+				reason = hooks.ExitPanicked
+			}
+			w.scheduler.WorkerExit(reason)
 			_ = w.scheduler.PutCache(w) // 将对象放在缓冲池中
-			w.scheduler.Recover()       // 有报错就处理报错
+			w.scheduler.Recover(p)      // 有报错就处理报错
 		}()
+		if w.lockThread {
+			// 不解除绑定，goroutine退出时线程随之销毁，线程局部状态不会被其他goroutine复用
+			runtime.LockOSThread()
+		}
+		w.scheduler.WorkerStart()
+		w.info = ExecInfo{Prepare: w.prepare}
+		if w.scheduler.TracksTasks() {
+			w.info.GoroutineID = stats.CurrentGoroutineID() // 每个goroutine只获取一次
+		}
+		if w.start != nil {
+			w.start()
+		}
+		if w.stop != nil {
+			defer w.stop() // 先于归还缓冲池执行
+		}
 
 		for {
 			select {
 			case <-w.exit:
 				return
 			case task := <-w.task:
-				handler := w.scheduler.Handler() // 获取该scheduler的handler处理函数
-				handler(task)                    // 执行task
+				w.execute(task) // 执行task，panic在其中恢复并处理，worker继续运行
+				w.tasks++
+				if w.scheduler.Recycle(w.tasks, w.createdTime) {
+					reason = hooks.ExitRecycled
+					return // 达到回收条件，主动退出
+				}
 				if err := w.scheduler.PutReady(w); err != nil {
+					reason = hooks.ExitClosed
 					return
 				}
 			}
@@ -37,12 +91,25 @@ func (w *worker[T]) Run() {
 	}()
 }
 
+// 执行任务，prepare在暂停与丢弃检查之后调用，失败时任务不执行而是按scheduler的Fail处理，worker继续就绪并在下个任务前重试
+func (w *worker[T]) execute(task taskCtx[T]) {
+	w.scheduler.ExecuteWith(task.ctx, task.value, w.info, w.handler())
+}
+
+// 获取任务处理函数，优先使用worker自身的handle
+func (w *worker[T]) handler() func(T) {
+	if w.handle != nil {
+		return w.handle
+	}
+	return w.scheduler.Handler()
+}
+
 func (w *worker[T]) Finish() {
 	w.exit <- struct{}{}
 }
 
 func (w *worker[T]) Refresh() {
-	w.usedTime = time.Now()
+	w.usedTime = w.scheduler.Now()
 }
 
 func (w *worker[T]) GetUsedTime() time.Time {
@@ -51,9 +118,9 @@ func (w *worker[T]) GetUsedTime() time.Time {
 
 func NewWorker[T any](s Scheduler[T]) Worker[T] {
 	return &worker[T]{
-		task:      make(chan T, 1),
+		task:      make(chan taskCtx[T], 1),
 		exit:      make(chan struct{}, 1),
 		scheduler: s,
-		usedTime:  time.Now(),
+		usedTime:  s.Now(),
 	}
 }
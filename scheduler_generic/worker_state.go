@@ -0,0 +1,50 @@
+package scheduler_generic
+
+import (
+	"fmt"
+
+	"github.com/gaohao-creator/turbopool/errors"
+)
+
+// 创建带状态的worker工厂。
+// worker的goroutine启动时调用init创建状态，任务以(状态, 任务)交给handler处理，
+// goroutine退出时（Finish、过期清理、释放）调用close释放状态。
+// init失败时worker会在下一个任务前重试，重试仍失败时任务不执行，交给调度器的Fail记录并报告。
+func NewWorkerWithState[S, T any](
+	init func() (S, error),
+	handler func(S, T),
+	close func(S),
+) func(Scheduler[T]) Worker[T] {
+	return func(s Scheduler[T]) Worker[T] {
+		w := NewWorker(s).(*worker[T])
+		var (
+			state S
+			ready bool // 状态是否已初始化
+		)
+		w.prepare = func() error {
+			if ready {
+				return nil
+			}
+			st, err := init()
+			if err != nil {
+				return fmt.Errorf("%w: %w", errors.ErrorWorkerInit, err)
+			}
+			state, ready = st, true
+			return nil
+		}
+		w.start = func() {
+			_ = w.prepare() // 失败时在任务到来前重试
+		}
+		w.handle = func(task T) {
+			handler(state, task)
+		}
+		w.stop = func() {
+			if ready && close != nil {
+				close(state)
+			}
+			var zero S
+			state, ready = zero, false
+		}
+		return w
+	}
+}
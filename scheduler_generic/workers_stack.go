@@ -16,6 +16,8 @@ type WorkersStack[T any] struct {
 
 // Get stack length.
 func (s *WorkersStack[T]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	return len(s.data)
 }
 
@@ -28,7 +30,7 @@ func (s *WorkersStack[T]) IsEmpty() bool {
 func (s *WorkersStack[T]) Push(w Worker[T]) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	if s.Len() < s.size {
+	if len(s.data) < s.size {
 		s.data = append(s.data, w)
 		return nil
 	}
@@ -38,7 +40,7 @@ func (s *WorkersStack[T]) Push(w Worker[T]) error {
 // Get a worker and remove it.
 func (s *WorkersStack[T]) Pop() (Worker[T], error) {
 	s.lock.Lock()
-	if s.IsEmpty() {
+	if len(s.data) == 0 {
 		s.lock.Unlock()
 		return nil, errors.ErrorWorkersIsEmpty
 	}
@@ -53,11 +55,11 @@ func (s *WorkersStack[T]) Pop() (Worker[T], error) {
 // Clear all worker.
 func (s *WorkersStack[T]) Clear() error {
 	s.lock.Lock()
-	if s.IsEmpty() {
+	if len(s.data) == 0 {
 		s.lock.Unlock()
 		return nil
 	}
-	for i := 0; i < s.Len(); i++ {
+	for i := 0; i < len(s.data); i++ {
 		w := s.data[i]
 		s.data[i] = nil
 		w.Finish()
@@ -67,10 +69,15 @@ func (s *WorkersStack[T]) Clear() error {
 	return nil
 }
 
-// Clear expired worker.
+// Clear expired worker, return cleared count.
 func (s *WorkersStack[T]) ClearExpired(t time.Time) (int, error) {
+	return s.ClearExpiredKeep(t, 0)
+}
+
+// Clear expired worker, keep at least keep workers, return cleared count.
+func (s *WorkersStack[T]) ClearExpiredKeep(t time.Time, keep int) (int, error) {
 	s.lock.Lock()
-	if s.IsEmpty() {
+	if len(s.data) == 0 {
 		s.lock.Unlock()
 		return 0, nil
 	}
@@ -79,7 +86,11 @@ func (s *WorkersStack[T]) ClearExpired(t time.Time) (int, error) {
 	index := sort.Search(len(s.data), func(i int) bool {
 		return t.Before(s.data[i].GetUsedTime())
 	})
-	if index < 0 {
+	// Keep the newest workers when min idle is required.
+	if limit := len(s.data) - keep; index > limit {
+		index = limit
+	}
+	if index <= 0 {
 		s.lock.Unlock()
 		return 0, nil
 	}
@@ -95,11 +106,25 @@ func (s *WorkersStack[T]) ClearExpired(t time.Time) (int, error) {
 	j := copy(s.data, s.data[index:])
 	s.data = s.data[:j] // slice data reset len.
 	s.lock.Unlock()
-	return j, nil
+	return index, nil
+}
+
+// Get last used time of each worker, in push order.
+func (s *WorkersStack[T]) UsedTimes() []time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	times := make([]time.Time, len(s.data))
+	for i, w := range s.data {
+		times[i] = w.GetUsedTime()
+	}
+	return times
 }
 
 // Scale capacity.
 func (s *WorkersStack[T]) Scale(cap int32) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.size = int(cap)
 	return nil
 }
 
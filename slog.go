@@ -0,0 +1,72 @@
+package turbopool
+
+import (
+	"context"
+	"log/slog"
+)
+
+// 结构化日志各类事件的级别
+type SlogLevels struct {
+	Panic          slog.Level // 任务panic
+	Reject         slog.Level // 提交被拒绝
+	Expiry         slog.Level // 过期worker清理
+	Resize         slog.Level // 容量调整，见 Scale
+	ReleaseTimeout slog.Level // 释放超时
+	SlowTask       slog.Level // 慢任务
+	WorkerInit     slog.Level // worker初始化失败，见 NewPoolWithState
+}
+
+// 默认的结构化日志级别
+func DefaultSlogLevels() SlogLevels {
+	return SlogLevels{
+		Panic:          slog.LevelError,
+		Reject:         slog.LevelWarn,
+		Expiry:         slog.LevelDebug,
+		Resize:         slog.LevelInfo,
+		ReleaseTimeout: slog.LevelWarn,
+		SlowTask:       slog.LevelWarn,
+		WorkerInit:     slog.LevelWarn,
+	}
+}
+
+// 结构化日志事件，用于 WithSlogLevel 单独设置某类事件的级别
+type SlogEvent int
+
+const (
+	SlogEventPanic          SlogEvent = iota // 任务panic，对应 SlogLevels.Panic
+	SlogEventReject                          // 提交被拒绝
+	SlogEventExpiry                          // 过期worker清理
+	SlogEventResize                          // 容量调整
+	SlogEventReleaseTimeout                  // 释放超时
+	SlogEventSlowTask                        // 慢任务
+	SlogEventWorkerInit                      // worker初始化失败
+)
+
+// 获取事件对应的级别字段，未知事件返回nil
+func (l *SlogLevels) level(event SlogEvent) *slog.Level {
+	switch event {
+	case SlogEventPanic:
+		return &l.Panic
+	case SlogEventReject:
+		return &l.Reject
+	case SlogEventExpiry:
+		return &l.Expiry
+	case SlogEventResize:
+		return &l.Resize
+	case SlogEventReleaseTimeout:
+		return &l.ReleaseTimeout
+	case SlogEventSlowTask:
+		return &l.SlowTask
+	case SlogEventWorkerInit:
+		return &l.WorkerInit
+	}
+	return nil
+}
+
+// 输出结构化日志，调用方应先判断 opts.Slog 是否为空，避免构造属性的开销
+func (opts *Options) logAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	if opts.Slog == nil {
+		return
+	}
+	opts.Slog.LogAttrs(context.Background(), level, msg, attrs...)
+}
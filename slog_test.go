@@ -0,0 +1,117 @@
+package turbopool
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/clock/clocktest"
+)
+
+// 并发安全的日志缓冲
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+// 解析出所有日志记录
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func (b *syncBuffer) find(t *testing.T, msg string) map[string]any {
+	for _, record := range b.records(t) {
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func TestPoolWithSlog(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fc := clocktest.NewFakeClock(time.Now())
+	pool, _ := NewPoolWithFuncDefaultHandler(
+		1,
+		WithNonblocking(true),
+		WithExpiryDuration(time.Second),
+		WithClock(fc),
+		WithSlog(logger),
+		WithSlogLevel(SlogEventReject, slog.LevelInfo), // 只改拒绝事件，Info 同样生效，其余保留默认
+	)
+
+	// panic 日志携带堆栈
+	_ = pool.Submit(func() { panic("boom") })
+	waitFor(t, func() bool { return buf.find(t, "task panicked") != nil }, "panic not logged")
+	record := buf.find(t, "task panicked")
+	if record["level"] != "ERROR" || record["panic"] != "boom" || !strings.Contains(record["stack"].(string), "goroutine") {
+		t.Fatalf("panic record = %v", record)
+	}
+
+	// 过期清理
+	done := make(chan struct{})
+	_ = pool.Submit(func() { close(done) })
+	<-done
+	waitFor(t, func() bool {
+		if buf.find(t, "expired workers cleared") != nil {
+			return true
+		}
+		fc.Advance(2 * time.Second)
+		return false
+	}, "expiry not logged")
+
+	// 拒绝使用配置的级别
+	gate := make(chan struct{})
+	_ = pool.Submit(func() { <-gate })
+	_ = pool.Submit(func() {})
+	if record := buf.find(t, "submit rejected"); record == nil || record["level"] != "INFO" {
+		t.Fatalf("reject record = %v", record)
+	}
+
+	// 容量调整
+	pool.Scale(2)
+	if record := buf.find(t, "pool resized"); record == nil || record["level"] != "INFO" || record["to"] != float64(2) {
+		t.Fatalf("resize record = %v", record)
+	}
+
+	// 释放超时
+	_ = pool.ReleaseWithTimeout(10 * time.Millisecond)
+	close(gate)
+	if record := buf.find(t, "release pool timeout"); record == nil || record["level"] != "WARN" {
+		t.Fatalf("release timeout record = %v", record)
+	}
+}
+
+// WithSlogLevel 只改单个事件，设为 Info 同样生效
+func TestSlogLevelOptions(t *testing.T) {
+	levels := NewOptions(WithSlogLevel(SlogEventPanic, slog.LevelInfo)).SlogLevels
+	want := DefaultSlogLevels()
+	want.Panic = slog.LevelInfo
+	if levels != want {
+		t.Fatalf("levels = %+v, want %+v", levels, want)
+	}
+}
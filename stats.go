@@ -0,0 +1,17 @@
+package turbopool
+
+import "github.com/gaohao-creator/turbopool/stats"
+
+// 池子运行状态快照，由 Pool.Stats / PoolWithFunc.Stats 返回
+type Stats = stats.Stats
+
+// 耗时直方图快照
+type HistogramSnapshot = stats.HistogramSnapshot
+
+// 获取直方图快照，未开启时返回零值
+func snapshotHistogram(h *stats.Histogram) HistogramSnapshot {
+	if h == nil {
+		return HistogramSnapshot{}
+	}
+	return h.Snapshot()
+}
@@ -0,0 +1,26 @@
+package stats
+
+import "time"
+
+// 就绪队列中的空闲worker
+type ReadyWorker struct {
+	LastUsed time.Time     // 上次运行的时间
+	Idle     time.Duration // 已空闲的时长
+}
+
+// 阻塞等待worker的提交方
+type Waiter struct {
+	ID    uint64    // 等待序号，按开始等待的顺序递增
+	Since time.Time // 开始等待的时间
+}
+
+// 池子的调试快照，供调试页面展示，各部分分别采集，彼此之间不保证严格一致
+type Debug struct {
+	Now          time.Time         // 采集时间
+	State        string            // 池子状态：opened / paused / closed
+	Options      map[string]string // 配置选项
+	Stats        Stats             // 运行状态
+	ReadyWorkers []ReadyWorker     // 就绪worker，按入栈顺序（最久未用的在前）；自定义容器未实现 UsedTimesReader 时为空
+	RunningTasks []RunningTask     // 正在执行的任务，开启任务登记时才有
+	Waiters      []Waiter          // 阻塞等待的提交方
+}
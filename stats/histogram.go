@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// 直方图桶数量：桶0统计 <1.024µs 的样本，桶i统计 [2^(i+9), 2^(i+10)) ns，最后一个桶统计其余所有样本
+const HistogramBuckets = 32
+
+// Histogram 固定对数刻度的耗时直方图，Record 无锁且不分配内存
+type Histogram struct {
+	buckets [HistogramBuckets]atomic.Int64
+	count   atomic.Int64
+	sum     atomic.Int64 // 纳秒
+}
+
+// 记录一次耗时
+func (h *Histogram) Record(d time.Duration) {
+	h.buckets[bucketIndex(d)].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// 获取直方图快照
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+		Buckets: make([]int64, HistogramBuckets),
+	}
+	for i := range h.buckets {
+		s.Buckets[i] = h.buckets[i].Load()
+	}
+	s.P50 = s.Percentile(0.50)
+	s.P90 = s.Percentile(0.90)
+	s.P99 = s.Percentile(0.99)
+	return s
+}
+
+// HistogramSnapshot 直方图快照，未开启直方图时为零值
+type HistogramSnapshot struct {
+	Count   int64         // 样本数
+	Sum     time.Duration // 耗时总和
+	Buckets []int64       // 各桶样本数（非累计），上界见 BucketUpperBound
+	P50     time.Duration // 50分位（所在桶上界）
+	P90     time.Duration // 90分位（所在桶上界）
+	P99     time.Duration // 99分位（所在桶上界）
+}
+
+// 计算q分位（0~1）的耗时，返回所在桶的上界，最后一个桶返回其下界
+func (s HistogramSnapshot) Percentile(q float64) time.Duration {
+	var total int64
+	for _, n := range s.Buckets {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(total)))
+	var cumulative int64
+	for i, n := range s.Buckets {
+		cumulative += n
+		if cumulative >= rank && n > 0 {
+			if i == HistogramBuckets-1 {
+				return BucketUpperBound(i - 1)
+			}
+			return BucketUpperBound(i)
+		}
+	}
+	return BucketUpperBound(HistogramBuckets - 2)
+}
+
+// 第i个桶的上界（不含），最后一个桶没有上界，返回 math.MaxInt64
+func BucketUpperBound(i int) time.Duration {
+	if i >= HistogramBuckets-1 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(1) << (i + 10)
+}
+
+func bucketIndex(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return min(bits.Len64(uint64(d)>>10), HistogramBuckets-1)
+}
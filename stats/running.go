@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunningTask 正在执行的任务。
+// 登记时只保存任务值和提交方ctx，Task 和 Labels 由 Describe 填充，格式化的开销只在巡检报告或调试快照时产生
+type RunningTask struct {
+	ID          uint64            // 任务ID，按开始顺序递增
+	StartedAt   time.Time         // 开始执行的时间
+	GoroutineID uint64            // 执行任务的worker goroutine ID
+	Task        string            // 任务描述，见 DescribeTask
+	Labels      map[string]string // 提交方ctx中的pprof标签（如 SubmitWithLabels 设置的），没有时为nil
+
+	value any             // 任务值
+	ctx   context.Context // 提交方ctx
+}
+
+// 填充任务描述和标签
+func (t *RunningTask) Describe() {
+	t.Task = DescribeTask(t.value)
+	if t.ctx != nil {
+		t.Labels = ContextLabels(t.ctx)
+	}
+}
+
+// 任务描述的最大长度，超出部分截断
+const maxTaskDescription = 256
+
+// RunningTasks 正在执行任务的登记表
+type RunningTasks struct {
+	lock   sync.Mutex
+	nextID uint64
+	tasks  map[uint64]RunningTask
+}
+
+// 登记开始执行的任务，ID由登记表分配并返回；value与ctx原样保存，不做格式化
+func (r *RunningTasks) Start(startedAt time.Time, goroutineID uint64, value any, ctx context.Context) uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.nextID++
+	r.tasks[r.nextID] = RunningTask{
+		ID:          r.nextID,
+		StartedAt:   startedAt,
+		GoroutineID: goroutineID,
+		value:       value,
+		ctx:         ctx,
+	}
+	return r.nextID
+}
+
+// 注销执行结束的任务
+func (r *RunningTasks) Finish(id uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.tasks, id)
+}
+
+// 获取正在执行的任务，按开始时间排序；需要任务描述和标签时调用 Describe
+func (r *RunningTasks) List() []RunningTask {
+	r.lock.Lock()
+	tasks := make([]RunningTask, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, task)
+	}
+	r.lock.Unlock()
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// 创建任务登记表
+func NewRunningTasks() *RunningTasks {
+	return &RunningTasks{tasks: make(map[uint64]RunningTask)}
+}
+
+// 获取当前goroutine的ID（解析堆栈头 "goroutine 123 [running]:"），开销较大，worker在goroutine启动时获取一次
+func CurrentGoroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// 获取指定goroutine当前的堆栈，goroutine不存在时返回空字符串
+func GoroutineStack(id uint64) string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	start := bytes.Index(buf, header)
+	if start < 0 {
+		return ""
+	}
+	stack := buf[start:]
+	if end := bytes.Index(stack, []byte("\n\n")); end >= 0 {
+		stack = stack[:end]
+	}
+	return string(stack)
+}
+
+// 生成任务描述：函数取其函数名，其他值按 %v 格式化，过长时截断
+func DescribeTask(task any) string {
+	if v := reflect.ValueOf(task); v.Kind() == reflect.Func && !v.IsNil() {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	desc := fmt.Sprintf("%v", task)
+	if len(desc) > maxTaskDescription {
+		desc = strings.ToValidUTF8(desc[:maxTaskDescription], "") + "..."
+	}
+	return desc
+}
+
+// 获取ctx中的pprof标签，没有时返回nil
+func ContextLabels(ctx context.Context) map[string]string {
+	var labels map[string]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+		return true
+	})
+	return labels
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"sync/atomic"
+
+	"github.com/gaohao-creator/turbopool/breaker"
+)
+
+// Stats 池子运行状态快照
+type Stats struct {
+	// 容量与当前状态
+	Cap     int32 // worker总容量
+	Running int32 // 正在运行的worker数量（忙碌 + 就绪）
+	Waiting int32 // 阻塞等待worker的任务数量
+	Busy    int32 // 正在执行任务的worker数量
+	Idle    int32 // 就绪队列中空闲的worker数量
+
+	// 任务统计
+	Submitted int64 // 成功分配到worker的任务数
+	Completed int64 // 正常执行完成的任务数
+	Failed    int64 // 因调度器关闭、worker初始化失败等原因未能执行的任务数
+	Panicked  int64 // 执行中发生panic的任务数
+	Rejected  int64 // 调度器已满、熔断器打开或池子已关闭被拒绝的任务数
+	TimedOut  int64 // 等待worker超时的任务数
+	Dropped   int64 // 释放时被丢弃的任务数
+
+	// worker统计
+	WorkersCreated     int64 // 启动过的worker数量
+	WorkersExpired     int64 // 空闲过期被清理的worker数量
+	WorkersRecycled    int64 // 达到回收条件主动退出的worker数量
+	RecycledByTasks    int64 // 因任务数达到上限而回收的worker数量
+	RecycledByLifetime int64 // 因存活时长达到上限而回收的worker数量
+
+	// 峰值
+	PeakRunning int32 // 运行worker数量峰值
+	PeakWaiting int32 // 等待任务数量峰值
+
+	// 熔断器，未开启时为关闭状态
+	BreakerState breaker.State // 熔断器状态
+	BreakerOpens int64         // 熔断器累计打开次数
+
+	// 耗时分布，开启直方图后才有数据
+	WaitLatency HistogramSnapshot // 任务等待worker的耗时
+	ExecLatency HistogramSnapshot // 任务执行耗时
+}
+
+// StoreMax 当v小于n时将其更新为n，用于记录峰值
+func StoreMax(v *atomic.Int32, n int32) {
+	for {
+		old := v.Load()
+		if n <= old || v.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import "context"
+
+// span名称
+const (
+	SpanWait    = "turbopool.wait"    // 提交方等待worker
+	SpanExecute = "turbopool.execute" // worker执行任务
+)
+
+// Tracer 追踪抽象，不依赖具体的追踪库。
+// Start 应从ctx中提取父span并创建子span，返回携带新span的ctx。
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span 一次被追踪的操作
+type Span interface {
+	SetAttribute(key string, value any) // 设置属性
+	RecordError(err error)              // 记录错误
+	End()                               // 结束
+}
+
+type spanKey struct{}
+
+// 将span放入ctx，供没有自带上下文传播的Tracer实现使用
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// 从ctx中取出span，不存在时返回nil
+func SpanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value any) {}
+
+func (noopSpan) RecordError(err error) {}
+
+func (noopSpan) End() {}
+
+// 创建不做任何事情的Tracer，作为默认值
+func NewNoopTracer() Tracer {
+	return noopTracer{}
+}
+
+// 判断是否为默认的空Tracer
+func IsNoop(t Tracer) bool {
+	_, ok := t.(noopTracer)
+	return ok
+}
@@ -0,0 +1,103 @@
+package tracingtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/tracing"
+)
+
+// RecordedSpan 记录下来的span
+type RecordedSpan struct {
+	ID         uint64
+	ParentID   uint64 // 没有父span时为0
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Errors     []error
+	Ended      bool
+}
+
+// Recorder 将span记录在内存中的Tracer，用于测试
+type Recorder struct {
+	lock  sync.Mutex
+	spans []*RecordedSpan
+}
+
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, tracing.Span) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	span := &recorderSpan{
+		recorder: r,
+		data: &RecordedSpan{
+			ID:         uint64(len(r.spans) + 1),
+			Name:       name,
+			Start:      time.Now(),
+			Attributes: map[string]any{},
+		},
+	}
+	if parent, ok := tracing.SpanFromContext(ctx).(*recorderSpan); ok {
+		span.data.ParentID = parent.data.ID
+	}
+	r.spans = append(r.spans, span.data)
+	return tracing.ContextWithSpan(ctx, span), span
+}
+
+// 获取所有span的副本，按创建顺序排列
+func (r *Recorder) Spans() []RecordedSpan {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	spans := make([]RecordedSpan, 0, len(r.spans))
+	for _, span := range r.spans {
+		copied := *span
+		copied.Attributes = make(map[string]any, len(span.Attributes))
+		for k, v := range span.Attributes {
+			copied.Attributes[k] = v
+		}
+		copied.Errors = append([]error(nil), span.Errors...)
+		spans = append(spans, copied)
+	}
+	return spans
+}
+
+// 按名称查找已结束的span
+func (r *Recorder) Ended(name string) []RecordedSpan {
+	var spans []RecordedSpan
+	for _, span := range r.Spans() {
+		if span.Name == name && span.Ended {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+type recorderSpan struct {
+	recorder *Recorder
+	data     *RecordedSpan
+}
+
+func (s *recorderSpan) SetAttribute(key string, value any) {
+	s.recorder.lock.Lock()
+	defer s.recorder.lock.Unlock()
+	s.data.Attributes[key] = value
+}
+
+func (s *recorderSpan) RecordError(err error) {
+	s.recorder.lock.Lock()
+	defer s.recorder.lock.Unlock()
+	s.data.Errors = append(s.data.Errors, err)
+}
+
+func (s *recorderSpan) End() {
+	s.recorder.lock.Lock()
+	defer s.recorder.lock.Unlock()
+	s.data.End = time.Now()
+	s.data.Ended = true
+}
+
+// 创建内存记录器
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
@@ -0,0 +1,99 @@
+package turbopool
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gaohao-creator/turbopool/stats"
+)
+
+// 执行时间超过阈值的任务
+type SlowTask struct {
+	TaskID      uint64            // 任务ID
+	StartedAt   time.Time         // 开始执行的时间
+	Elapsed     time.Duration     // 发现时已执行的时长
+	GoroutineID uint64            // 执行任务的worker goroutine ID
+	Task        string            // 任务描述：函数为函数名，其他任务值按 %v 格式化
+	Labels      map[string]string // 提交时设置的pprof标签，如 SubmitWithLabels 的labels
+	Stack       string            // 发现时worker goroutine的堆栈
+}
+
+// 启动慢任务巡检goroutine，周期性检查正在执行的任务，每个慢任务只报告一次；c取消时退出
+func startWatchdog(c context.Context, opts *Options, runningTasks func() []stats.RunningTask) {
+	if opts.WatchdogThreshold <= 0 {
+		return
+	}
+	interval := opts.WatchdogInterval
+	if interval <= 0 {
+		interval = opts.WatchdogThreshold
+	}
+	ticker := opts.Clock.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		reported := make(map[uint64]struct{}) // 已报告的任务ID
+		for {
+			select {
+			case <-c.Done():
+				return
+			case <-ticker.C():
+			}
+			checkSlowTasks(opts, runningTasks(), reported)
+		}
+	}()
+}
+
+func checkSlowTasks(opts *Options, tasks []stats.RunningTask, reported map[uint64]struct{}) {
+	now := opts.Clock.Now()
+	running := make(map[uint64]struct{}, len(tasks))
+	for _, task := range tasks {
+		running[task.ID] = struct{}{}
+		if _, ok := reported[task.ID]; ok {
+			continue
+		}
+		elapsed := now.Sub(task.StartedAt)
+		if elapsed < opts.WatchdogThreshold {
+			continue
+		}
+		reported[task.ID] = struct{}{}
+		task.Describe() // 只为要报告的任务格式化
+		reportSlowTask(opts, SlowTask{
+			TaskID:      task.ID,
+			StartedAt:   task.StartedAt,
+			Elapsed:     elapsed,
+			GoroutineID: task.GoroutineID,
+			Task:        task.Task,
+			Labels:      task.Labels,
+			Stack:       stats.GoroutineStack(task.GoroutineID),
+		})
+	}
+	// 清理已结束任务的报告记录
+	for id := range reported {
+		if _, ok := running[id]; !ok {
+			delete(reported, id)
+		}
+	}
+}
+
+// 报告慢任务，优先使用回调，其次是结构化日志和普通日志
+func reportSlowTask(opts *Options, task SlowTask) {
+	if opts.OnSlowTask != nil {
+		opts.OnSlowTask(task)
+		return
+	}
+	if opts.Slog != nil {
+		opts.logAttrs(opts.SlogLevels.SlowTask, "slow task detected",
+			slog.Uint64("task_id", task.TaskID),
+			slog.String("task", task.Task),
+			slog.Any("labels", task.Labels),
+			slog.Time("started_at", task.StartedAt),
+			slog.Duration("elapsed", task.Elapsed),
+			slog.Uint64("goroutine_id", task.GoroutineID),
+			slog.String("stack", task.Stack))
+		return
+	}
+	if logger := opts.Logger; logger != nil {
+		logger.Printf("slow task %d (%s %v) running for %v since %v\n%s\n",
+			task.TaskID, task.Task, task.Labels, task.Elapsed, task.StartedAt, task.Stack)
+	}
+}